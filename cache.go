@@ -0,0 +1,190 @@
+package validator
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize is the default maximum number of validators kept in the cache.
+const DefaultCacheSize = 1024
+
+// CacheStats contains statistics about the cache of validators.
+type CacheStats struct {
+	// Number of times a validator was found in the cache
+	Hits uint64
+	// Number of times a validator was not in the cache and had to be created
+	Misses uint64
+	// Number of validators that were removed from the cache because it was full
+	Evictions uint64
+	// Number of validators currently in the cache
+	Size int
+	// Maximum number of validators in the cache; 0 if the cache is disabled
+	Capacity int
+}
+
+// Cache of validators, used by Validate, ValidateAny, and ValidateStruct
+var validators = newValidatorCache(DefaultCacheSize)
+
+// SetCacheSize sets the maximum number of validators kept in the cache, which are evicted in least-recently-used order.
+// Setting the size to 0 disables the cache, so rules are parsed every time a value is validated; use Compile for rules that are used often.
+// If the new size is smaller than the number of validators in the cache, the least recently used ones are evicted.
+// Negative values are treated as 0.
+func SetCacheSize(size int) {
+	validators.resize(size)
+}
+
+// PurgeCache removes all validators from the cache.
+// Statistics are not reset.
+func PurgeCache() {
+	validators.purge()
+}
+
+// GetCacheStats returns statistics about the cache of validators.
+func GetCacheStats() CacheStats {
+	return validators.stats()
+}
+
+// validatorCache is a size-bounded LRU cache for validators
+// Concurrent requests for the same key that is not in the cache are deduplicated, so each validator is created only once
+type validatorCache struct {
+	lock     sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[validatorCacheKey]*list.Element
+	inflight map[validatorCacheKey]*cacheCall
+	// Incremented when the cache is purged, so validators created before that are not stored
+	generation uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// cacheEntry is an entry in the LRU list
+type cacheEntry struct {
+	key   validatorCacheKey
+	value any
+}
+
+// cacheCall is a validator that is being created
+type cacheCall struct {
+	done  chan struct{}
+	value any
+}
+
+// newValidatorCache returns a new validatorCache with the given capacity
+func newValidatorCache(capacity int) *validatorCache {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &validatorCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    map[validatorCacheKey]*list.Element{},
+		inflight: map[validatorCacheKey]*cacheCall{},
+	}
+}
+
+// getOrCreate returns the value for the key from the cache, invoking create to make it if it's not in the cache
+// If another goroutine is already creating the value for the same key, it waits for that instead
+func (c *validatorCache) getOrCreate(key validatorCacheKey, create func() any) any {
+	c.lock.Lock()
+	if el, ok := c.items[key]; ok {
+		c.hits++
+		c.ll.MoveToFront(el)
+		c.lock.Unlock()
+		return el.Value.(*cacheEntry).value
+	}
+	c.misses++
+	if call, ok := c.inflight[key]; ok {
+		c.lock.Unlock()
+		<-call.done
+		if call.value != nil {
+			return call.value
+		}
+		// The other goroutine panicked while creating the value
+		return create()
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	generation := c.generation
+	c.lock.Unlock()
+
+	// Ensure waiting goroutines are released even if create panics
+	defer func() {
+		c.lock.Lock()
+		if c.inflight[key] == call {
+			delete(c.inflight, key)
+		}
+		if call.value != nil && generation == c.generation {
+			c.add(key, call.value)
+		}
+		c.lock.Unlock()
+		close(call.done)
+	}()
+
+	call.value = create()
+	return call.value
+}
+
+// add stores a value in the cache, evicting the least recently used ones if needed
+// It must be invoked while holding the lock
+func (c *validatorCache) add(key validatorCacheKey, value any) {
+	if c.capacity == 0 {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).value = value
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, value: value})
+	c.evict()
+}
+
+// evict removes the least recently used values until the cache is within its capacity
+// It must be invoked while holding the lock
+func (c *validatorCache) evict() {
+	for c.ll.Len() > c.capacity {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// resize changes the capacity of the cache
+func (c *validatorCache) resize(capacity int) {
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.capacity = capacity
+	c.evict()
+}
+
+// purge removes all values from the cache
+func (c *validatorCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.ll.Init()
+	c.items = map[validatorCacheKey]*list.Element{}
+	// Validators that are being created may be stale too, so new requests don't wait for them
+	c.inflight = map[validatorCacheKey]*cacheCall{}
+	c.generation++
+}
+
+// stats returns the statistics for the cache
+func (c *validatorCache) stats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.ll.Len(),
+		Capacity:  c.capacity,
+	}
+}
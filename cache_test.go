@@ -0,0 +1,157 @@
+package validator
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidatorCache(t *testing.T) {
+	key := func(rule string) validatorCacheKey {
+		return validatorCacheKey{typ: reflect.TypeOf(""), rule: rule}
+	}
+
+	t.Run("LRU eviction", func(t *testing.T) {
+		c := newValidatorCache(2)
+		created := 0
+		get := func(rule string) any {
+			return c.getOrCreate(key(rule), func() any {
+				created++
+				return rule
+			})
+		}
+
+		get("a")
+		get("b")
+		if get("a") != "a" {
+			t.Error("getOrCreate() returned the wrong value")
+		}
+		// "b" is the least recently used value, so it's evicted
+		get("c")
+		get("a")
+		get("b")
+
+		if created != 4 {
+			t.Errorf("values created = %d, want 4", created)
+		}
+		want := CacheStats{Hits: 2, Misses: 4, Evictions: 2, Size: 2, Capacity: 2}
+		if got := c.stats(); got != want {
+			t.Errorf("stats() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("resize and purge", func(t *testing.T) {
+		c := newValidatorCache(3)
+		for _, rule := range []string{"a", "b", "c"} {
+			c.getOrCreate(key(rule), func() any { return rule })
+		}
+
+		c.resize(1)
+		if got := c.stats(); got.Size != 1 || got.Evictions != 2 {
+			t.Errorf("stats() after resize = %+v", got)
+		}
+
+		c.purge()
+		if got := c.stats(); got.Size != 0 || got.Capacity != 1 {
+			t.Errorf("stats() after purge = %+v", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c := newValidatorCache(0)
+		created := 0
+		for i := 0; i < 3; i++ {
+			c.getOrCreate(key("a"), func() any {
+				created++
+				return "a"
+			})
+		}
+		if created != 3 {
+			t.Errorf("values created = %d, want 3", created)
+		}
+		if got := c.stats(); got.Size != 0 || got.Misses != 3 {
+			t.Errorf("stats() = %+v", got)
+		}
+	})
+
+	t.Run("concurrent creation is deduplicated", func(t *testing.T) {
+		c := newValidatorCache(10)
+		var created int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res := c.getOrCreate(key("a"), func() any {
+					atomic.AddInt32(&created, 1)
+					time.Sleep(20 * time.Millisecond)
+					return "a"
+				})
+				if res != "a" {
+					t.Errorf("getOrCreate() = %v, want a", res)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := atomic.LoadInt32(&created); n != 1 {
+			t.Errorf("values created = %d, want 1", n)
+		}
+	})
+
+	t.Run("purge while creating", func(t *testing.T) {
+		c := newValidatorCache(10)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.getOrCreate(key("a"), func() any {
+				close(started)
+				<-release
+				return "stale"
+			})
+		}()
+
+		<-started
+		c.purge()
+		close(release)
+		<-done
+
+		// The value created before the purge must not be stored
+		res := c.getOrCreate(key("a"), func() any { return "fresh" })
+		if res != "fresh" {
+			t.Errorf("getOrCreate() = %v, want fresh", res)
+		}
+	})
+}
+
+func TestCacheFunctions(t *testing.T) {
+	defer SetCacheSize(DefaultCacheSize)
+
+	PurgeCache()
+	before := GetCacheStats()
+	if before.Size != 0 || before.Capacity != DefaultCacheSize {
+		t.Fatalf("GetCacheStats() = %+v", before)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := Validate(" hello ", "max=10"); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+	}
+	stats := GetCacheStats()
+	if stats.Size != 1 || stats.Misses-before.Misses != 1 || stats.Hits-before.Hits != 2 {
+		t.Errorf("GetCacheStats() = %+v, before = %+v", stats, before)
+	}
+
+	SetCacheSize(0)
+	if _, err := Validate(" hello ", "max=10"); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if stats := GetCacheStats(); stats.Size != 0 || stats.Capacity != 0 {
+		t.Errorf("GetCacheStats() with disabled cache = %+v", stats)
+	}
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Rule is a rule that was compiled for values of type T with Compile or MustCompile.
+// Validating values with a Rule object doesn't require parsing the rule or looking up the validator in the cache, so it's faster than calling Validate in hot paths.
+// Rule objects are safe for concurrent use.
+type Rule[T any] struct {
+	rule      string
+	validator validator[T]
+	isEmpty   func(T) bool
+}
+
+// Compile parses the rule for values of type T, and returns a Rule object that can be used to validate values.
+// It returns an error if the rule has invalid syntax or parameters, or if there's no validator for the type T.
+// Supported types are the same as for Validate.
+func Compile[T any](rule string) (*Rule[T], error) {
+	rule = strings.TrimSpace(rule)
+
+	validator, err := typeValidator[T](rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Rule[T]{
+		rule:      rule,
+		validator: validator,
+		isEmpty:   emptyFunc[T](),
+	}, nil
+}
+
+// emptyFunc returns a function that reports whether a value of type T is an empty string, slice, or map, or nil if values of type T are never skipped
+func emptyFunc[T any]() func(T) bool {
+	// Like in Validate, empty strings, slices, and maps are returned as-is, unless there's a custom validator registered for their type
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if _, isCustom := getCustomType(t); isCustom {
+		return nil
+	}
+
+	// Common types get a typed check, so validating them doesn't allocate
+	var f any
+	switch any((*T)(nil)).(type) {
+	case *string:
+		f = func(val string) bool { return val == "" }
+	case *[]string:
+		f = func(val []string) bool { return len(val) == 0 }
+	case *map[string]string:
+		f = func(val map[string]string) bool { return len(val) == 0 }
+	}
+	if f != nil {
+		return f.(func(T) bool)
+	}
+
+	// Other types, such as named types and nested collections, fall back to reflection
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return func(val T) bool {
+			return reflect.ValueOf(&val).Elem().Len() == 0
+		}
+	}
+	return nil
+}
+
+// MustCompile is like Compile but it panics if the rule can't be compiled.
+// It's meant to be used to initialize package-level variables.
+func MustCompile[T any](rule string) *Rule[T] {
+	r, err := Compile[T](rule)
+	if err != nil {
+		panic(fmt.Sprintf("validator: failed to compile rule %q: %v", rule, err))
+	}
+	return r
+}
+
+// Validate validates and sanitizes a value using the compiled rule.
+func (r *Rule[T]) Validate(val T) (T, error) {
+	if r.isEmpty != nil && r.isEmpty(val) {
+		return val, nil
+	}
+	return r.validator(val)
+}
+
+// String returns the rule string.
+func (r *Rule[T]) String() string {
+	return r.rule
+}
@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		r, err := Compile[string](" max=5 ")
+		if err != nil {
+			t.Fatalf("Compile() error = %v", err)
+		}
+		if r.String() != "max=5" {
+			t.Errorf("Rule.String() = %q, want %q", r.String(), "max=5")
+		}
+
+		res, err := r.Validate("  hello ")
+		if err != nil || res != "hello" {
+			t.Errorf("Rule.Validate() = %q, %v, want %q, nil", res, err, "hello")
+		}
+		_, err = r.Validate("hello world")
+		if !errors.Is(err, ErrTooLong) {
+			t.Errorf("Rule.Validate() error = %v, want ErrTooLong", err)
+		}
+	})
+
+	t.Run("empty values are skipped", func(t *testing.T) {
+		res, err := MustCompile[string]("min=3").Validate("")
+		if err != nil || res != "" {
+			t.Errorf("Rule.Validate() = %q, %v, want empty string and no error", res, err)
+		}
+		resSlice, err := MustCompile[[]string]("min=3").Validate(nil)
+		if err != nil || resSlice != nil {
+			t.Errorf("Rule.Validate() = %v, %v, want nil and no error", resSlice, err)
+		}
+	})
+
+	t.Run("empty values don't allocate", func(t *testing.T) {
+		rs := MustCompile[string]("min=3")
+		rSlice := MustCompile[[]string]("min=3")
+		rMap := MustCompile[map[string]string]("min=3")
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = rs.Validate("")
+			_, _ = rSlice.Validate(nil)
+			_, _ = rMap.Validate(nil)
+		})
+		if allocs != 0 {
+			t.Errorf("Rule.Validate() allocations = %v, want 0", allocs)
+		}
+	})
+
+	t.Run("named empty values are skipped", func(t *testing.T) {
+		type email string
+		res, err := MustCompile[email]("min=3").Validate("")
+		if err != nil || res != "" {
+			t.Errorf("Rule.Validate() = %q, %v, want empty string and no error", res, err)
+		}
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		r := MustCompile[int]("min=1,max=10,clamp")
+		res, err := r.Validate(0)
+		if err != nil || res != 1 {
+			t.Errorf("Rule.Validate() = %d, %v, want 1, nil", res, err)
+		}
+	})
+
+	t.Run("nested collections", func(t *testing.T) {
+		type tags []string
+		r := MustCompile[map[string]tags]("value=(value=(max=3),sort)")
+		res, err := r.Validate(map[string]tags{"a": {"b ", " a"}})
+		if err != nil {
+			t.Fatalf("Rule.Validate() error = %v", err)
+		}
+		want := map[string]tags{"a": {"a", "b"}}
+		if !reflect.DeepEqual(res, want) {
+			t.Errorf("Rule.Validate() = %v, want %v", res, want)
+		}
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := Compile[string]("min=a")
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("Compile() error = %v, want ErrInvalidParameter", err)
+		}
+		_, err = Compile[[]string]("value=(max=0)")
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("Compile() error = %v, want ErrInvalidParameter", err)
+		}
+		_, err = Compile[string]("min=(1")
+		if !errors.Is(err, ErrRuleSyntax) {
+			t.Errorf("Compile() error = %v, want ErrRuleSyntax", err)
+		}
+		_, err = Compile[struct{}]("")
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("Compile() error = %v, want ErrUnsupportedType", err)
+		}
+	})
+
+	t.Run("MustCompile panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("MustCompile() did not panic")
+			}
+		}()
+		MustCompile[int]("min=5,max=1")
+	})
+}
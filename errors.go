@@ -0,0 +1,236 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors, which can be used with errors.Is to determine the reason why a validation failed
+var (
+	// ErrRuleSyntax is returned when a rule string can't be parsed
+	ErrRuleSyntax = errors.New("invalid rule string: syntax error")
+	// ErrInvalidParameter is returned when a parameter in a rule is invalid
+	ErrInvalidParameter = errors.New("invalid rule parameter")
+	// ErrUnsupportedType is returned when there's no validator for the type of a value
+	ErrUnsupportedType = errors.New("cannot find a validator for type")
+	// ErrTooShort is returned when a string, slice, or map is shorter than the value of the "min" rule
+	ErrTooShort = errors.New("value is too short")
+	// ErrTooLong is returned when a string, slice, or map is longer than the value of the "max" rule
+	ErrTooLong = errors.New("value is too long")
+	// ErrTooSmall is returned when a number is smaller than the value of the "min" rule
+	ErrTooSmall = errors.New("value is too small")
+	// ErrTooLarge is returned when a number is greater than the value of the "max" rule
+	ErrTooLarge = errors.New("value is too large")
+	// ErrNotPositive is returned when a number is not greater than zero and the rule "positive" is set
+	ErrNotPositive = errors.New("value is not positive")
+	// ErrZero is returned when a number is zero and the rule "nonzero" is set
+	ErrZero = errors.New("value is zero")
+	// ErrNotFinite is returned when a float is NaN or infinite and the rule "finite" is set (or when it's NaN and there are range rules)
+	ErrNotFinite = errors.New("value is not finite")
+	// ErrStep is returned when a number is not a multiple of the value of the "step" rule
+	ErrStep = errors.New("value is not a multiple of step")
+	// ErrPatternMismatch is returned when a string doesn't match the regular expression in the "match" rule
+	ErrPatternMismatch = errors.New("value does not match the pattern")
+	// ErrPatternForbidden is returned when a string matches the regular expression in the "notmatch" rule
+	ErrPatternForbidden = errors.New("value matches a forbidden pattern")
+	// ErrNotAllowed is returned when a string is not one of the values allowed by the "oneof" or "in" rules
+	ErrNotAllowed = errors.New("value is not allowed")
+	// ErrNotASCII is returned when a string contains non-ASCII characters and the rule "ascii=reject" is set
+	ErrNotASCII = errors.New("value contains non-ASCII characters")
+	// ErrConfusable is returned when a string mixes scripts or numbering systems and the rule "noconfusables" is set
+	ErrConfusable = errors.New("value contains confusable characters")
+	// ErrBidiControl is returned when a string contains bidirectional control characters and the rule "nobidi-controls" is set
+	ErrBidiControl = errors.New("value contains bidirectional control characters")
+	// ErrBidiRule is returned when a string doesn't satisfy the Bidi Rule from RFC 5893 and the rule "bidi=strict" is set
+	ErrBidiRule = errors.New("value does not satisfy the bidi rule")
+	// ErrEmoji is returned when a string contains emoji and the rule "emoji=reject" is set
+	ErrEmoji = errors.New("value contains emoji")
+	// ErrInvalidEmoji is returned when a string contains an emoji sequence that is not well-formed and the rule "emoji=rgi-only" is set
+	ErrInvalidEmoji = errors.New("value contains an invalid emoji sequence")
+	// ErrTooManyEmoji is returned when a string contains more emoji than the value of the "maxemoji" rule
+	ErrTooManyEmoji = errors.New("value contains too many emoji")
+	// ErrCharNotAllowed is returned when a string contains a character that is not allowed by the "chars" or "scripts" rules
+	ErrCharNotAllowed = errors.New("value contains a character that is not allowed")
+	// ErrKeyCollision is returned when two keys in a map are the same after sanitization and the rule "oncollision=error" is set
+	ErrKeyCollision = errors.New("keys are the same after sanitization")
+	// ErrRulePanic is returned when a custom rule panics
+	ErrRulePanic = errors.New("custom rule panicked")
+)
+
+// ValidationError is the error returned when a value fails validation.
+// Use errors.Is with one of the sentinel errors (such as ErrTooShort) to check the reason of the failure.
+type ValidationError struct {
+	// Path of the value that failed validation, for example `Tags[3]` or `Labels["env"]`.
+	// It's empty if the error is about the value passed to the validator itself.
+	Path string
+	// Name of the rule that failed, for example "min"
+	Rule string
+	// Parameter of the rule that failed, for example "3" for "min=3"
+	Param string
+	// Length of the value that failed validation: for strings, this is in the unit set with the "lenunit" rule (bytes by default); for slices and maps, this is the number of elements.
+	// It's -1 for values that don't have a length, such as numbers.
+	Len int
+	// Err is the underlying error, usually one of the sentinel errors
+	Err error
+
+	msg string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	msg := e.msg
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if e.Path == "" {
+		return msg
+	}
+	return e.Path + ": " + msg
+}
+
+// Unwrap returns the underlying error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newValidationError returns a new ValidationError
+func newValidationError(err error, rule string, param string, length int, format string, a ...any) *ValidationError {
+	return &ValidationError{
+		Rule:  rule,
+		Param: param,
+		Len:   length,
+		Err:   err,
+		msg:   fmt.Sprintf(format, a...),
+	}
+}
+
+// invalidParamError returns an error for an invalid parameter in a rule
+func invalidParamError(param string, format string, a ...any) *ValidationError {
+	return newValidationError(ErrInvalidParameter, param, "", -1, format, a...)
+}
+
+// RuleSyntaxError is the error returned when a rule string can't be parsed.
+// It wraps ErrRuleSyntax.
+type RuleSyntaxError struct {
+	// Rule string that contains the error
+	Rule string
+	// Position of the error in the rule string, as a byte offset
+	Offset int
+	// Position of the error in the rule string, as a 1-based column counted in characters
+	Column int
+
+	msg string
+}
+
+// Error implements the error interface
+func (e *RuleSyntaxError) Error() string {
+	return fmt.Sprintf("invalid rule string %q: %s at column %d", e.Rule, e.msg, e.Column)
+}
+
+// Unwrap returns ErrRuleSyntax
+func (e *RuleSyntaxError) Unwrap() error {
+	return ErrRuleSyntax
+}
+
+// ValidationErrors is a list of errors returned when validating with the "all-errors" rule, or with ValidateStructAll.
+// Errors are sorted in a deterministic order: by index for slices, by key for maps, and by field order for structs.
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the list of errors, for use with errors.Is and errors.As in Go 1.20 and newer
+func (e ValidationErrors) Unwrap() []error {
+	res := make([]error, len(e))
+	for i, err := range e {
+		res[i] = err
+	}
+	return res
+}
+
+// Is returns true if any error in the list matches target, for use with errors.Is
+// This is required because errors.Is doesn't use Unwrap() []error before Go 1.20
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in the list that matches target, and if one is found, sets target to that error value and returns true, for use with errors.As
+// This is required because errors.As doesn't use Unwrap() []error before Go 1.20
+func (e ValidationErrors) As(target any) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// append adds err to the list, flattening it if it's a ValidationErrors too
+func (e ValidationErrors) append(err error) ValidationErrors {
+	switch x := err.(type) {
+	case ValidationErrors:
+		return append(e, x...)
+	case *ValidationError:
+		return append(e, x)
+	default:
+		return append(e, &ValidationError{Len: -1, Err: err, msg: err.Error()})
+	}
+}
+
+// prependErrorPath returns an error whose path has the segment prepended to it
+// If err is not a ValidationError or ValidationErrors, it's wrapped in a ValidationError
+func prependErrorPath(err error, segment string) error {
+	if errs, ok := err.(ValidationErrors); ok {
+		res := make(ValidationErrors, len(errs))
+		for i, e := range errs {
+			res[i] = prependErrorPath(e, segment).(*ValidationError)
+		}
+		return res
+	}
+
+	var res ValidationError
+	e, ok := err.(*ValidationError)
+	if ok {
+		res = *e
+	} else {
+		res = ValidationError{
+			Len: -1,
+			Err: err,
+			msg: err.Error(),
+		}
+	}
+
+	switch {
+	case res.Path == "":
+		res.Path = segment
+	case strings.HasPrefix(res.Path, "["):
+		res.Path = segment + res.Path
+	default:
+		res.Path = segment + "." + res.Path
+	}
+	return &res
+}
+
+// keyError returns an error for a map key that failed validation
+func keyError(err error) error {
+	e, ok := err.(*ValidationError)
+	if !ok {
+		return fmt.Errorf("invalid key: %w", err)
+	}
+
+	res := *e
+	res.msg = "invalid key: " + e.Error()
+	return &res
+}
@@ -0,0 +1,266 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func() error
+		wantIs    error
+		wantPath  string
+		wantRule  string
+		wantParam string
+		wantLen   int
+		wantMsg   string
+	}{
+		{
+			name: "string too short",
+			validate: func() error {
+				_, err := Validate("hi", "min=3")
+				return err
+			},
+			wantIs:    ErrTooShort,
+			wantRule:  "min",
+			wantParam: "3",
+			wantLen:   2,
+			wantMsg:   "value is shorter than 3",
+		},
+		{
+			name: "slice element too long",
+			validate: func() error {
+				_, err := Validate([]string{"a", "b", "c", "hello"}, "value=(max=3)")
+				return err
+			},
+			wantIs:    ErrTooLong,
+			wantPath:  "[3]",
+			wantRule:  "max",
+			wantParam: "3",
+			wantLen:   5,
+			wantMsg:   "[3]: value is longer than 3",
+		},
+		{
+			name: "map value too short",
+			validate: func() error {
+				_, err := Validate(map[string]string{"env": "a"}, "value=(min=2)")
+				return err
+			},
+			wantIs:    ErrTooShort,
+			wantPath:  `["env"]`,
+			wantRule:  "min",
+			wantParam: "2",
+			wantLen:   1,
+			wantMsg:   `["env"]: value is shorter than 2`,
+		},
+		{
+			name: "map key too short",
+			validate: func() error {
+				_, err := Validate(map[string]string{"e": "abc"}, "key=(min=2)")
+				return err
+			},
+			wantIs:    ErrTooShort,
+			wantPath:  `["e"]`,
+			wantRule:  "min",
+			wantParam: "2",
+			wantLen:   1,
+			wantMsg:   `["e"]: invalid key: value is shorter than 2`,
+		},
+		{
+			name: "nested collections",
+			validate: func() error {
+				_, err := Validate([]map[string]string{{}, {"env": "hello"}}, "value=(value=(max=2))")
+				return err
+			},
+			wantIs:    ErrTooLong,
+			wantPath:  `[1]["env"]`,
+			wantRule:  "max",
+			wantParam: "2",
+			wantLen:   5,
+			wantMsg:   `[1]["env"]: value is longer than 2`,
+		},
+		{
+			name: "number too large",
+			validate: func() error {
+				_, err := Validate(12, "max=10")
+				return err
+			},
+			wantIs:    ErrTooLarge,
+			wantRule:  "max",
+			wantParam: "10",
+			wantLen:   -1,
+			wantMsg:   "value is greater than 10",
+		},
+		{
+			name: "struct field",
+			validate: func() error {
+				val := struct {
+					Tags []string `validate:"value=(min=2)"`
+				}{
+					Tags: []string{"foo", "a"},
+				}
+				return ValidateStruct(&val)
+			},
+			wantIs:    ErrTooShort,
+			wantPath:  "Tags[1]",
+			wantRule:  "min",
+			wantParam: "2",
+			wantLen:   1,
+			wantMsg:   "Tags[1]: value is shorter than 2",
+		},
+		{
+			name: "invalid parameter",
+			validate: func() error {
+				_, err := Validate("foo", "min=0")
+				return err
+			},
+			wantIs:   ErrInvalidParameter,
+			wantRule: "min",
+			wantLen:  -1,
+			wantMsg:  "parameter 'min' must be greater than 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if !errors.Is(err, tt.wantIs) {
+				t.Fatalf("error = %v, want %v", err, tt.wantIs)
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("error is not a ValidationError: %T", err)
+			}
+			if vErr.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", vErr.Path, tt.wantPath)
+			}
+			if vErr.Rule != tt.wantRule {
+				t.Errorf("Rule = %q, want %q", vErr.Rule, tt.wantRule)
+			}
+			if vErr.Param != tt.wantParam {
+				t.Errorf("Param = %q, want %q", vErr.Param, tt.wantParam)
+			}
+			if vErr.Len != tt.wantLen {
+				t.Errorf("Len = %d, want %d", vErr.Len, tt.wantLen)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+
+	t.Run("syntax error", func(t *testing.T) {
+		_, err := Validate("foo", "min=(3")
+		if !errors.Is(err, ErrRuleSyntax) {
+			t.Errorf("error = %v, want %v", err, ErrRuleSyntax)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := ValidateAny(struct{}{}, "")
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("error = %v, want %v", err, ErrUnsupportedType)
+		}
+	})
+
+	t.Run("all errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			validate  func() error
+			wantMsg   string
+			wantPaths []string
+		}{
+			{
+				name: "slice",
+				validate: func() error {
+					_, err := Validate([]string{"a", "hello", "b", "world"}, "value=(max=3),max=3,all-errors")
+					return err
+				},
+				wantMsg:   "value is longer than 3; [1]: value is longer than 3; [3]: value is longer than 3",
+				wantPaths: []string{"", "[1]", "[3]"},
+			},
+			{
+				name: "map sorted by key",
+				validate: func() error {
+					_, err := Validate(map[string]string{"z": "hello", "b": "ok", "a": "world", "c": "x"}, "key=(max=1),value=(min=2,max=3),all-errors")
+					return err
+				},
+				wantMsg:   `["a"]: value is longer than 3; ["c"]: value is shorter than 2; ["z"]: value is longer than 3`,
+				wantPaths: []string{`["a"]`, `["c"]`, `["z"]`},
+			},
+			{
+				name: "nested collections are flattened",
+				validate: func() error {
+					_, err := Validate([][]string{{"hello", "a"}, {"world", "hi"}}, "value=(value=(max=2),all-errors),all-errors")
+					return err
+				},
+				wantMsg:   "[0][0]: value is longer than 2; [1][0]: value is longer than 2",
+				wantPaths: []string{"[0][0]", "[1][0]"},
+			},
+			{
+				name: "struct",
+				validate: func() error {
+					val := struct {
+						Name  string            `validate:"min=3"`
+						Tags  []string          `validate:"value=(max=2),all-errors"`
+						Count int               `validate:"max=5"`
+						Map   map[string]string `validate:"value=(max=2)"`
+					}{
+						Name:  "a",
+						Tags:  []string{"foo", "ok", "bar"},
+						Count: 10,
+						Map:   map[string]string{"a": "ok"},
+					}
+					return ValidateStructAll(&val)
+				},
+				wantMsg:   "Name: value is shorter than 3; Tags[0]: value is longer than 2; Tags[2]: value is longer than 2; Count: value is greater than 5",
+				wantPaths: []string{"Name", "Tags[0]", "Tags[2]", "Count"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Run multiple times to make sure the order is deterministic
+				for i := 0; i < 10; i++ {
+					err := tt.validate()
+					var errs ValidationErrors
+					if !errors.As(err, &errs) {
+						t.Fatalf("error is not a ValidationErrors: %T (%v)", err, err)
+					}
+					if err.Error() != tt.wantMsg {
+						t.Fatalf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+					}
+					if len(errs) != len(tt.wantPaths) {
+						t.Fatalf("got %d errors, want %d", len(errs), len(tt.wantPaths))
+					}
+					for j, e := range errs {
+						if e.Path != tt.wantPaths[j] {
+							t.Errorf("errs[%d].Path = %q, want %q", j, e.Path, tt.wantPaths[j])
+						}
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Is and As on ValidationErrors", func(t *testing.T) {
+		// Call the methods directly, as errors.Is and errors.As can also use Unwrap() []error in Go 1.20 and newer
+		_, err := Validate(map[string]string{"ENV": "a", "env": "b", "x": "toolong"}, "key=(case=lower),value=(max=3),oncollision=error,all-errors")
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("error is not a ValidationErrors: %T (%v)", err, err)
+		}
+		if !errs.Is(ErrKeyCollision) {
+			t.Error("Is(ErrKeyCollision) = false, want true")
+		}
+		if !errs.Is(ErrTooLong) {
+			t.Error("Is(ErrTooLong) = false, want true")
+		}
+		if errs.Is(ErrTooShort) {
+			t.Error("Is(ErrTooShort) = true, want false")
+		}
+		var ve *ValidationError
+		if !errs.As(&ve) || ve != errs[0] {
+			t.Errorf("As() = %v, want %v", ve, errs[0])
+		}
+	})
+}
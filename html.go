@@ -0,0 +1,158 @@
+package validator
+
+import (
+	"html"
+	"strings"
+)
+
+// Maximum length of an HTML entity, such as "&CounterClockwiseContourIntegral;"
+const maxHTMLEntityLength = 33
+
+// HTML elements whose contents are removed together with the tags
+var htmlRawTextElements = map[string]struct{}{
+	"script": {},
+	"style":  {},
+}
+
+// HTML elements that are replaced with a newline when stripping tags, so the text in adjacent blocks is not joined
+var htmlBlockElements = map[string]struct{}{
+	"address": {}, "article": {}, "aside": {}, "blockquote": {}, "br": {}, "dd": {}, "details": {}, "dialog": {},
+	"div": {}, "dl": {}, "dt": {}, "fieldset": {}, "figcaption": {}, "figure": {}, "footer": {}, "form": {},
+	"h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}, "header": {}, "hr": {}, "li": {}, "main": {},
+	"nav": {}, "ol": {}, "p": {}, "pre": {}, "section": {}, "summary": {}, "table": {}, "td": {}, "th": {},
+	"tr": {}, "ul": {},
+}
+
+// stripHTML removes HTML tags, comments, and the contents of script and style elements from s, and decodes HTML entities in the text that is left, in a single pass
+// Block-level elements (such as paragraphs) are replaced with a newline
+// Characters that don't start a tag, such as the "<" in "a < b", are kept as-is, and the contents of CDATA sections are kept without decoding entities
+// The result is text and not HTML, as it can contain "<" and ">" characters, for example from the entities "&lt;" and "&gt;"
+func stripHTML(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			sb.WriteString(html.UnescapeString(s))
+			break
+		}
+		sb.WriteString(html.UnescapeString(s[:i]))
+		s = s[i:]
+
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			// Comment
+			s = skipPast(s[4:], "-->")
+		case strings.HasPrefix(s, "<![CDATA["):
+			// CDATA section, whose contents are kept
+			end := strings.Index(s, "]]>")
+			if end < 0 {
+				sb.WriteString(s[9:])
+				s = ""
+			} else {
+				sb.WriteString(s[9:end])
+				s = s[end+3:]
+			}
+		case len(s) > 1 && (s[1] == '!' || s[1] == '?'):
+			// Doctype or processing instruction
+			s = skipPast(s[2:], ">")
+		default:
+			name, rest, ok := parseHTMLTag(s)
+			if !ok {
+				// Not a tag
+				sb.WriteByte('<')
+				s = s[1:]
+				continue
+			}
+			s = rest
+			if _, ok := htmlRawTextElements[name]; ok {
+				// Skip until the closing tag
+				end := indexFold(s, "</"+name)
+				if end < 0 {
+					s = ""
+				} else {
+					s = skipPast(s[end:], ">")
+				}
+			}
+			if _, ok := htmlBlockElements[strings.TrimPrefix(name, "/")]; ok {
+				sb.WriteByte('\n')
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// Replacer that escapes the characters that can start or end tags
+var htmlAngleBracketsEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+// parseHTMLTag parses an opening or closing tag at the beginning of s
+// It returns the lowercase name of the tag (with a leading "/" for closing tags) and the rest of the string after the tag
+// If s doesn't start with a tag, it returns false
+func parseHTMLTag(s string) (name string, rest string, ok bool) {
+	// Tag names must start with a letter
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		i++
+	}
+	if i >= len(s) || !isASCIILetter(s[i]) {
+		return "", "", false
+	}
+	for i < len(s) && (isASCIILetter(s[i]) || (s[i] >= '0' && s[i] <= '9') || s[i] == '-') {
+		i++
+	}
+	name = strings.ToLower(s[1:i])
+
+	// Skip attributes until the end of the tag, ignoring ">" inside quoted values
+	var quote byte
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return name, s[i+1:], true
+		}
+	}
+
+	// Unterminated tags are removed until the end of the string
+	return name, "", true
+}
+
+// skipPast returns the part of s after the first occurrence of sep, or an empty string if sep is not found
+func skipPast(s string, sep string) string {
+	i := strings.Index(s, sep)
+	if i < 0 {
+		return ""
+	}
+	return s[i+len(sep):]
+}
+
+// indexFold returns the index of the first occurrence of the ASCII string substr in s, ignoring case, or -1 if it's not found
+func indexFold(s string, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isHTMLEntityName returns true if s can be the name of an HTML entity (the part between "&" and ";"), such as "amp" or "#34"
+func isHTMLEntityName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isASCIILetter(s[i]) && !(s[i] >= '0' && s[i] <= '9') && !(i == 0 && s[i] == '#') {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCIILetter returns true if c is an ASCII letter
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_stripHTML(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "hello world", want: "hello world"},
+		{value: "<b>bold</b> and <i>italic</i>", want: "bold and italic"},
+		{value: "<p>first</p><p>second</p>", want: "\nfirst\n\nsecond\n"},
+		{value: "line<br/>break", want: "line\nbreak"},
+		{value: `<a href="https://example.com/?a>b" title='x>y'>link</a>`, want: "link"},
+		{value: "before<!-- comment <b>x</b> -->after", want: "beforeafter"},
+		{value: "a<script>alert('<b>hi</b>')</script>b", want: "ab"},
+		{value: "a<STYLE type=\"text/css\">p { color: red; }</Style>b", want: "ab"},
+		{value: "<!DOCTYPE html><html><body>text</body></html>", want: "text"},
+		{value: "<![CDATA[raw text]]>", want: "raw text"},
+		{value: "<![CDATA[raw <text>]]>", want: "raw <text>"},
+		{value: "<![CDATA[a &amp; b]]>", want: "a &amp; b"},
+		{value: "Tom &amp; Jerry &lt;3 &#x1F600;", want: "Tom & Jerry <3 \U0001F600"},
+		{value: "use the &lt;b&gt; tag for bold", want: "use the <b> tag for bold"},
+		{value: "<<b>script>alert(1)<</b>/script>", want: "<script>alert(1)</script>"},
+		{value: "a < b and c > d", want: "a < b and c > d"},
+		{value: "1<2", want: "1<2"},
+		{value: "unterminated <b", want: "unterminated "},
+		{value: "unterminated <script>alert(1)", want: "unterminated "},
+		{value: "unterminated <!-- comment", want: "unterminated "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := stripHTML(tt.value); got != tt.want {
+				t.Errorf("stripHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_stripHTMLLargeInput(t *testing.T) {
+	// Inputs where removing tags joins the text around them into new tags must be processed in linear time
+	inputs := []string{
+		strings.Repeat("<<b>script>", 50_000),
+		strings.Repeat("<", 50_000) + strings.Repeat("b>", 50_000),
+		strings.Repeat("<script>", 50_000),
+		strings.Repeat("&lt;b&gt;", 50_000),
+	}
+	for _, input := range inputs {
+		start := time.Now()
+		stripHTML(input)
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("stripHTML() took %v for an input of %d bytes", d, len(input))
+		}
+	}
+}
@@ -1,91 +1,370 @@
 package validator
 
 import (
-	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
-var ruleSyntaxError = errors.New("invalid rule string: syntax error")
+// ruleParam is a parameter in a rule string, such as `min=3` or `unique`
+type ruleParam struct {
+	// Key of the parameter
+	key string
+	// Value of the parameter, with quotes and escape sequences removed
+	// For values enclosed in parentheses, this contains the text between the parentheses as-is, so it can be parsed as a nested rule
+	value string
+	// True if the parameter has a value (`key=value`), false for boolean options
+	hasValue bool
+	// True if the value was enclosed in parentheses
+	nested bool
+	// Span of the parameter in the rule string, as byte offsets
+	start, end int
+}
 
-func parseParams(rule string) (params map[string]string, err error) {
-	l := len(rule)
-	if l == 0 {
-		return map[string]string{}, nil
+// subRule parses the value of the parameter as a nested rule
+func (p ruleParam) subRule() (ruleParams, error) {
+	return parseRule(p.value)
+}
+
+// ruleParams is the list of parameters in a rule string, in the order they appear
+type ruleParams []ruleParam
+
+// toMap returns the parameters as a map of keys to values
+func (rp ruleParams) toMap() map[string]string {
+	res := make(map[string]string, len(rp))
+	for _, p := range rp {
+		res[p.key] = p.value
 	}
+	return res
+}
 
-	var start, in, lastClosedParam, end int
-	var key, val string
-	for i := 0; i < l; i++ {
-		// Skip characters that are part of a multi-byte sequence
-		if rule[i] > 127 {
+// scalar returns the value of the parameter with the given key, for parameters whose value is a single string rather than a nested rule or a list
+// Values in parentheses are returned as-is, except when the text between the parentheses is a single quoted string, such as `("a)b")`, whose quotes are removed
+func (rp ruleParams) scalar(key string) (string, bool) {
+	for _, p := range rp {
+		if p.key != key {
 			continue
 		}
+		if !p.nested {
+			return p.value, true
+		}
+		sp := &ruleParser{rule: p.value}
+		sp.skipSpaces()
+		if sp.eof() || (sp.rule[sp.pos] != '"' && sp.rule[sp.pos] != '\'') {
+			return p.value, true
+		}
+		val, err := sp.parseQuoted()
+		sp.skipSpaces()
+		if err != nil || !sp.eof() {
+			return p.value, true
+		}
+		return val, true
+	}
+	return "", false
+}
+
+// parseParams parses a rule string and returns a map of keys to values
+func parseParams(rule string) (params map[string]string, err error) {
+	rp, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	return rp.toMap(), nil
+}
+
+// parseRule parses a rule string
+//
+// The format of a rule string is a comma-separated list of parameters, each in the format `key=value` or `key` (for boolean options).
+// Values can be:
+//
+// - Bare text, such as `min=3`: backslashes can be used to escape the characters `,=()"'\`
+// - Quoted text, such as `ellipsis="..."` or `ellipsis='...'`: inside quotes, all characters are literal except for `\"` (or `\'`) and `\\`
+// - Text in parentheses, such as `value=(min=3,max=5)`: parentheses must be balanced, unless they're escaped or in quotes; the text is kept as-is so it can be parsed as a nested rule
+//
+// Whitespace around keys and values is ignored.
+// Keys can't appear more than once in the same rule.
+func parseRule(rule string) (ruleParams, error) {
+	p := &ruleParser{rule: rule}
+	return p.parse()
+}
+
+// ruleParser contains the state for parseRule
+type ruleParser struct {
+	rule string
+	pos  int
+}
+
+// parse parses the entire rule
+func (p *ruleParser) parse() (ruleParams, error) {
+	var res ruleParams
+	seen := map[string]struct{}{}
+	for {
+		p.skipSpaces()
+		// Allow empty rules and a trailing comma
+		if p.eof() {
+			break
+		}
 
-		if rule[i] == '(' {
-			if in == 0 {
-				start++
-			}
-			in++
-		} else if rule[i] == ')' {
-			in--
-			if in < 0 {
-				return nil, ruleSyntaxError
+		// Key
+		param := ruleParam{start: p.pos}
+		key, _, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			// Keys in parentheses or quotes can't be empty either
+			if p.pos > param.start {
+				return nil, p.errorAt(param.start, "empty key")
 			}
-			lastClosedParam = i
-		} else if in == 0 {
-			if rule[i] == ',' {
-				if params == nil {
-					params = map[string]string{}
-				}
-				end = i
-				if lastClosedParam == i-1 {
-					end--
-				}
-				val = rule[start:end]
-				// If there's no key, the we only have a key with no value
-				if key == "" {
-					if start == end {
-						return nil, ruleSyntaxError
-					}
-					params[val] = ""
-				} else {
-					params[key] = val
-					key = ""
-				}
-				start = i + 1
-			} else if rule[i] == '=' {
-				end = i
-				if lastClosedParam == i-1 {
-					end--
-				}
-				if start == end {
-					return nil, ruleSyntaxError
-				}
-				key = rule[start:end]
-				start = i + 1
+			return nil, p.unexpected()
+		}
+		param.key = key
+
+		// Value
+		p.skipSpaces()
+		if !p.eof() && p.rule[p.pos] == '=' {
+			p.pos++
+			p.skipSpaces()
+			param.hasValue = true
+			param.value, param.nested, err = p.parseAtom()
+			if err != nil {
+				return nil, err
 			}
 		}
+		param.end = p.pos
+
+		if _, ok := seen[key]; ok {
+			return nil, p.errorAt(param.start, "duplicate key '%s'", key)
+		}
+		seen[key] = struct{}{}
+		res = append(res, param)
+
+		// Separator
+		p.skipSpaces()
+		if p.eof() {
+			break
+		}
+		if p.rule[p.pos] != ',' {
+			return nil, p.unexpected()
+		}
+		p.pos++
 	}
-	if in != 0 {
-		return nil, ruleSyntaxError
+
+	return res, nil
+}
+
+// parseAtom parses a key or a value, which can be bare text, quoted text, or text in parentheses
+// It returns true as second value if the text was in parentheses
+func (p *ruleParser) parseAtom() (string, bool, error) {
+	if p.eof() {
+		return "", false, nil
+	}
+
+	switch p.rule[p.pos] {
+	case '(':
+		val, err := p.parseParens()
+		return val, true, err
+	case '"', '\'':
+		val, err := p.parseQuoted()
+		return val, false, err
+	default:
+		return p.parseBare(), false, nil
 	}
-	if start != l {
-		if params == nil {
-			params = map[string]string{}
+}
+
+// parseBare parses bare text, until the first character that is a separator or parenthesis
+func (p *ruleParser) parseBare() string {
+	var sb strings.Builder
+	// Length of the text without trailing whitespace
+	trimmed := 0
+	for !p.eof() {
+		c := p.rule[p.pos]
+		switch c {
+		case ',', '=', '(', ')':
+			return sb.String()[:trimmed]
+		case '\\':
+			if p.pos+1 < len(p.rule) && isRuleSpecialChar(p.rule[p.pos+1]) {
+				p.pos++
+				c = p.rule[p.pos]
+			}
 		}
-		end = l
-		if lastClosedParam == l-1 {
-			end--
+		sb.WriteByte(c)
+		p.pos++
+		if !isRuleSpace(c) {
+			trimmed = sb.Len()
 		}
-		if start == end {
-			return nil, ruleSyntaxError
+	}
+	return sb.String()[:trimmed]
+}
+
+// parseQuoted parses text in single or double quotes
+func (p *ruleParser) parseQuoted() (string, error) {
+	start := p.pos
+	quote := p.rule[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.rule[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\\' && p.pos+1 < len(p.rule) && (p.rule[p.pos+1] == quote || p.rule[p.pos+1] == '\\'):
+			p.pos++
+			c = p.rule[p.pos]
 		}
-		val = rule[start:end]
-		if key == "" {
-			params[val] = ""
+		sb.WriteByte(c)
+		p.pos++
+	}
+
+	return "", p.errorAt(start, "unterminated quoted string")
+}
+
+// parseParens parses text in parentheses, returning it as-is
+// Parentheses inside the text must be balanced, unless they're escaped or in quotes
+func (p *ruleParser) parseParens() (string, error) {
+	start := p.pos
+	p.pos++
+
+	depth := 1
+	// Quotes start a quoted string only at the beginning of a key or value
+	atomStart := true
+	for !p.eof() {
+		c := p.rule[p.pos]
+		switch {
+		case c == '\\':
+			// Skip the escaped character
+			p.pos++
+		case (c == '"' || c == '\'') && atomStart:
+			_, err := p.parseQuoted()
+			if err != nil {
+				return "", err
+			}
+			atomStart = false
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return p.rule[start+1 : p.pos-1], nil
+			}
+		}
+		atomStart = c == '(' || c == ',' || c == '=' || (atomStart && isRuleSpace(c))
+		p.pos++
+	}
+
+	return "", p.errorAt(start, "unclosed '('")
+}
+
+// skipSpaces advances past any whitespace
+func (p *ruleParser) skipSpaces() {
+	for !p.eof() && isRuleSpace(p.rule[p.pos]) {
+		p.pos++
+	}
+}
+
+// eof returns true if the parser reached the end of the rule
+func (p *ruleParser) eof() bool {
+	return p.pos >= len(p.rule)
+}
+
+// unexpected returns an error for the character at the current position
+func (p *ruleParser) unexpected() error {
+	if p.eof() {
+		return p.errorAt(p.pos, "unexpected end of rule")
+	}
+	r, _ := utf8.DecodeRuneInString(p.rule[p.pos:])
+	return p.errorAt(p.pos, "unexpected '%c'", r)
+}
+
+// errorAt returns a RuleSyntaxError for the given byte offset in the rule
+func (p *ruleParser) errorAt(pos int, format string, a ...any) error {
+	if pos > len(p.rule) {
+		pos = len(p.rule)
+	}
+	return &RuleSyntaxError{
+		Rule:   p.rule,
+		Offset: pos,
+		Column: utf8.RuneCountInString(p.rule[:pos]) + 1,
+		msg:    fmt.Sprintf(format, a...),
+	}
+}
+
+// isRuleSpace returns true if c is a whitespace character that is ignored around keys and values
+func isRuleSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isRuleSpecialChar returns true if c is a character that can be escaped with a backslash in bare text
+func isRuleSpecialChar(c byte) bool {
+	switch c {
+	case ',', '=', '(', ')', '"', '\'', '\\':
+		return true
+	default:
+		return false
+	}
+}
+
+// parseList parses a value that contains a list of items separated by sep, such as `a|b|c`
+// Whitespace around items is ignored, and items can be quoted like values in rules; outside of quotes, the separator and the characters `,=()"'\` can be escaped with a backslash
+// Items can't be empty
+func parseList(val string, sep byte) ([]string, error) {
+	p := &ruleParser{rule: val}
+	var res []string
+	for {
+		p.skipSpaces()
+		start := p.pos
+
+		var (
+			item string
+			err  error
+		)
+		if !p.eof() && (p.rule[p.pos] == '"' || p.rule[p.pos] == '\'') {
+			item, err = p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
 		} else {
-			params[key] = val
+			item = p.parseListItem(sep)
+		}
+		if p.pos == start {
+			return nil, p.errorAt(start, "empty item in list")
+		}
+		res = append(res, item)
+
+		p.skipSpaces()
+		if p.eof() {
+			return res, nil
+		}
+		if p.rule[p.pos] != sep {
+			return nil, p.unexpected()
 		}
+		p.pos++
 	}
+}
 
-	return params, nil
+// parseListItem parses an item in a list, until the first separator
+func (p *ruleParser) parseListItem(sep byte) string {
+	var sb strings.Builder
+	// Length of the text without trailing whitespace
+	trimmed := 0
+	for !p.eof() {
+		c := p.rule[p.pos]
+		if c == sep {
+			break
+		}
+		if c == '\\' && p.pos+1 < len(p.rule) && (p.rule[p.pos+1] == sep || isRuleSpecialChar(p.rule[p.pos+1])) {
+			p.pos++
+			c = p.rule[p.pos]
+		}
+		sb.WriteByte(c)
+		p.pos++
+		if !isRuleSpace(c) {
+			trimmed = sb.Len()
+		}
+	}
+	return sb.String()[:trimmed]
 }
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -112,6 +113,58 @@ func Test_parseParams(t *testing.T) {
 			args:    args{rule: "😃=😍"},
 			wantRes: map[string]string{"😃": "😍"},
 		},
+		{
+			args:    args{rule: " foo = bar , me "},
+			wantRes: map[string]string{"foo": "bar", "me": ""},
+		},
+		{
+			args:    args{rule: `foo="a,b=(c",bar='it\'s'`},
+			wantRes: map[string]string{"foo": "a,b=(c", "bar": "it's"},
+		},
+		{
+			args:    args{rule: `foo="^\d+\\$"`},
+			wantRes: map[string]string{"foo": `^\d+\$`},
+		},
+		{
+			args:    args{rule: `foo=a\,b\=c\(d\),bar=\d`},
+			wantRes: map[string]string{"foo": "a,b=c(d)", "bar": `\d`},
+		},
+		{
+			args:    args{rule: `foo=(a,")",b\))`},
+			wantRes: map[string]string{"foo": `a,")",b\)`},
+		},
+		{
+			args:    args{rule: "foo=(don't)"},
+			wantRes: map[string]string{"foo": "don't"},
+		},
+		{
+			args:    args{rule: "foo,"},
+			wantRes: map[string]string{"foo": ""},
+		},
+		{
+			args:    args{rule: "foo,,bar"},
+			wantErr: true,
+		},
+		{
+			args:    args{rule: "foo=1,foo=2"},
+			wantErr: true,
+		},
+		{
+			args:    args{rule: "foo=a=b"},
+			wantErr: true,
+		},
+		{
+			args:    args{rule: `foo="bar`},
+			wantErr: true,
+		},
+		{
+			args:    args{rule: `foo="bar"baz`},
+			wantErr: true,
+		},
+		{
+			args:    args{rule: "foo=(bar)baz"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		name := tt.name
@@ -130,3 +183,127 @@ func Test_parseParams(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseRule(t *testing.T) {
+	t.Run("AST", func(t *testing.T) {
+		rule := `min=3, value=(max=5,unique),ellipsis="…",trim`
+		got, err := parseRule(rule)
+		if err != nil {
+			t.Fatalf("parseRule() error = %v", err)
+		}
+		want := ruleParams{
+			{key: "min", value: "3", hasValue: true, start: 0, end: 5},
+			{key: "value", value: "max=5,unique", hasValue: true, nested: true, start: 7, end: 27},
+			{key: "ellipsis", value: "…", hasValue: true, start: 28, end: 42},
+			{key: "trim", start: 43, end: 47},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseRule() = %+v, want %+v", got, want)
+		}
+		if rule[got[1].start:got[1].end] != "value=(max=5,unique)" {
+			t.Errorf("unexpected span for nested rule: %q", rule[got[1].start:got[1].end])
+		}
+
+		sub, err := got[1].subRule()
+		if err != nil {
+			t.Fatalf("subRule() error = %v", err)
+		}
+		if len(sub) != 2 || sub[0].key != "max" || sub[1].key != "unique" {
+			t.Errorf("subRule() = %+v", sub)
+		}
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		tests := []struct {
+			rule    string
+			wantMsg string
+			wantCol int
+		}{
+			{rule: "min=3,max=(5", wantMsg: `invalid rule string "min=3,max=(5": unclosed '(' at column 11`, wantCol: 11},
+			{rule: "value=(min=1)),max=3", wantMsg: `invalid rule string "value=(min=1)),max=3": unexpected ')' at column 14`, wantCol: 14},
+			{rule: "min=1,min=2", wantMsg: `invalid rule string "min=1,min=2": duplicate key 'min' at column 7`, wantCol: 7},
+			{rule: "=foo", wantMsg: `invalid rule string "=foo": unexpected '=' at column 1`, wantCol: 1},
+			{rule: "ё=1,()", wantMsg: `invalid rule string "ё=1,()": empty key at column 5`, wantCol: 5},
+			{rule: `a="b`, wantMsg: `invalid rule string "a=\"b": unterminated quoted string at column 3`, wantCol: 3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.rule, func(t *testing.T) {
+				_, err := parseRule(tt.rule)
+				if !errors.Is(err, ErrRuleSyntax) {
+					t.Fatalf("parseRule() error = %v, want ErrRuleSyntax", err)
+				}
+				var sErr *RuleSyntaxError
+				if !errors.As(err, &sErr) {
+					t.Fatalf("parseRule() error is not a RuleSyntaxError")
+				}
+				if sErr.Column != tt.wantCol {
+					t.Errorf("Column = %d, want %d", sErr.Column, tt.wantCol)
+				}
+				if err.Error() != tt.wantMsg {
+					t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+				}
+			})
+		}
+	})
+}
+
+func Test_ruleParamsScalar(t *testing.T) {
+	tests := []struct {
+		rule   string
+		want   string
+		wantOk bool
+	}{
+		{rule: "foo=bar", want: "bar", wantOk: true},
+		{rule: `foo="a)b"`, want: "a)b", wantOk: true},
+		{rule: `foo=("a)b")`, want: "a)b", wantOk: true},
+		{rule: `foo=( 'it\'s' )`, want: "it's", wantOk: true},
+		{rule: `foo=("\"x\"")`, want: `"x"`, wantOk: true},
+		{rule: `foo=(^\(\d+\)$)`, want: `^\(\d+\)$`, wantOk: true},
+		{rule: `foo=("a"|b)`, want: `"a"|b`, wantOk: true},
+		{rule: `foo=(a,")",b\))`, want: `a,")",b\)`, wantOk: true},
+		{rule: "bar=1", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rp, err := parseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("parseRule() error = %v", err)
+			}
+			got, ok := rp.scalar("foo")
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("scalar() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_parseList(t *testing.T) {
+	tests := []struct {
+		val     string
+		sep     byte
+		want    []string
+		wantErr bool
+	}{
+		{val: "a|b|c", sep: '|', want: []string{"a", "b", "c"}},
+		{val: " a | b ", sep: '|', want: []string{"a", "b"}},
+		{val: `"a|b"|c`, sep: '|', want: []string{"a|b", "c"}},
+		{val: `a\|b|c`, sep: '|', want: []string{"a|b", "c"}},
+		{val: `a\,b|c`, sep: '|', want: []string{"a,b", "c"}},
+		{val: `a\(b\)|\\|c\d`, sep: '|', want: []string{"a(b)", `\`, `c\d`}},
+		{val: `L,\,`, sep: ',', want: []string{"L", ","}},
+		{val: `L,","`, sep: ',', want: []string{"L", ","}},
+		{val: "a||b", sep: '|', wantErr: true},
+		{val: `"a`, sep: '|', wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseList(tt.val, tt.sep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StringRuleFunc is the function that implements a custom string rule.
+// It receives the string after it has been sanitized, and it returns the (possibly transformed) value or an error if the value is not valid.
+type StringRuleFunc func(val string) (string, error)
+
+// StringRuleFactory is a function that returns a StringRuleFunc for a custom string rule.
+// It receives the value of the rule's parameter, which is an empty string for boolean rules (e.g. "sku" rather than "sku=param").
+// It's invoked once when a validator is created, and it can return an error if the parameter is invalid.
+type StringRuleFactory func(param string) (StringRuleFunc, error)
+
+var (
+	customStringRules     = map[string]StringRuleFactory{}
+	customStringRulesLock sync.RWMutex
+)
+
+// Names of the rules that are built into the string validator, which can't be used for custom rules
+var builtinStringRules = map[string]struct{}{
+	"min":                 {},
+	"max":                 {},
+	"preserve-whitespace": {},
+	"preserve-newlines":   {},
+	"replace-whitespaces": {},
+	"asciionly":           {},
+	"unorm":               {},
+	"match":               {},
+	"notmatch":            {},
+	"oneof":               {},
+	"in":                  {},
+	"ignorecase":          {},
+	"lenunit":             {},
+	"maxbytes":            {},
+	"truncate":            {},
+	"ellipsis":            {},
+	"case":                {},
+	"lang":                {},
+	"ascii":               {},
+	"slug":                {},
+	"noconfusables":       {},
+	"bidi":                {},
+	"nobidi-controls":     {},
+	"emoji":               {},
+	"maxemoji":            {},
+	"html":                {},
+	"chars":               {},
+	"scripts":             {},
+	"onbad":               {},
+}
+
+// RegisterStringRule registers a custom rule for the string validator, which can then be used in rules like the built-in ones.
+// For example, after registering a rule called "sku", it can be used in a rule such as "max=20,sku" or "sku=(param)".
+// Custom rules are executed after the string has been sanitized and before length rules are checked.
+// When a rule string contains multiple custom rules, they're executed in the order they appear in the rule string.
+// The name must not be the name of a built-in rule or of a custom rule that is already registered.
+// Registering a rule purges all cached validators.
+func RegisterStringRule(name string, factory StringRuleFactory) error {
+	if name == "" {
+		return errors.New("name must not be empty")
+	}
+	if factory == nil {
+		return errors.New("factory must not be nil")
+	}
+	if _, ok := builtinStringRules[name]; ok {
+		return fmt.Errorf("rule '%s' is a built-in rule", name)
+	}
+
+	customStringRulesLock.Lock()
+	defer customStringRulesLock.Unlock()
+	if _, ok := customStringRules[name]; ok {
+		return fmt.Errorf("rule '%s' is already registered", name)
+	}
+	customStringRules[name] = factory
+
+	// Cached validators may have been created ignoring the rule
+	validators.purge()
+
+	return nil
+}
+
+// customStringRuleFuncs returns the functions for the custom rules that are included in the rule, in the order they appear
+func customStringRuleFuncs(rp ruleParams) ([]StringRuleFunc, error) {
+	customStringRulesLock.RLock()
+	defer customStringRulesLock.RUnlock()
+
+	if len(customStringRules) == 0 {
+		return nil, nil
+	}
+
+	var res []StringRuleFunc
+	for _, p := range rp {
+		factory, ok := customStringRules[p.key]
+		if !ok {
+			continue
+		}
+		fn, err := callStringRuleFactory(p.key, p.value, factory)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, safeStringRuleFunc(p.key, p.value, fn))
+	}
+	return res, nil
+}
+
+// callStringRuleFactory invokes the factory for a custom rule, recovering from panics
+func callStringRuleFactory(name string, param string, factory StringRuleFactory) (fn StringRuleFunc, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newValidationError(ErrRulePanic, name, param, -1, "factory for rule '%s' panicked: %v", name, r)
+		}
+	}()
+
+	fn, err = factory(param)
+	if err != nil {
+		return nil, invalidParamError(name, "parameter '%s' is invalid: %v", name, err)
+	}
+	if fn == nil {
+		return nil, invalidParamError(name, "factory for rule '%s' returned a nil function", name)
+	}
+	return fn, nil
+}
+
+// safeStringRuleFunc wraps the function for a custom rule so it recovers from panics and returns ValidationError objects
+func safeStringRuleFunc(name string, param string, fn StringRuleFunc) StringRuleFunc {
+	return func(val string) (res string, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newValidationError(ErrRulePanic, name, param, len(val), "rule '%s' panicked: %v", name, r)
+			}
+		}()
+
+		res, err = fn(val)
+		if err != nil {
+			var vErr *ValidationError
+			if errors.As(err, &vErr) {
+				return "", err
+			}
+			return "", newValidationError(err, name, param, len(val), "%s", err.Error())
+		}
+		return res, nil
+	}
+}
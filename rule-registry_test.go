@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterStringRule(t *testing.T) {
+	errNotSKU := errors.New("value is not a valid SKU")
+
+	// Register the rules used in the tests
+	// Names are prefixed with "test-" to avoid conflicts with other tests
+	rules := map[string]StringRuleFactory{
+		"test-sku": func(param string) (StringRuleFunc, error) {
+			return func(val string) (string, error) {
+				if !strings.HasPrefix(val, "SKU-") {
+					return "", errNotSKU
+				}
+				return val, nil
+			}, nil
+		},
+		"test-prefix": func(param string) (StringRuleFunc, error) {
+			if param == "" {
+				return nil, errors.New("prefix is required")
+			}
+			return func(val string) (string, error) {
+				return param + val, nil
+			}, nil
+		},
+		"test-upper": func(param string) (StringRuleFunc, error) {
+			return func(val string) (string, error) {
+				return strings.ToUpper(val), nil
+			}, nil
+		},
+		"test-panic": func(param string) (StringRuleFunc, error) {
+			if param == "factory" {
+				panic("oh no")
+			}
+			return func(val string) (string, error) {
+				panic("oh no")
+			}, nil
+		},
+	}
+	// Remove the registered rules when the test ends, so it can be run multiple times
+	t.Cleanup(func() {
+		customStringRulesLock.Lock()
+		for name := range rules {
+			delete(customStringRules, name)
+		}
+		customStringRulesLock.Unlock()
+		validators.purge()
+	})
+	for name, factory := range rules {
+		err := RegisterStringRule(name, factory)
+		if err != nil {
+			t.Fatalf("RegisterStringRule(%s) error = %v", name, err)
+		}
+	}
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		noop := func(param string) (StringRuleFunc, error) {
+			return nil, nil
+		}
+		if err := RegisterStringRule("", noop); err == nil {
+			t.Error("expected error for empty name")
+		}
+		if err := RegisterStringRule("test-nil", nil); err == nil {
+			t.Error("expected error for nil factory")
+		}
+		if err := RegisterStringRule("max", noop); err == nil {
+			t.Error("expected error for built-in rule")
+		}
+		if err := RegisterStringRule("test-sku", noop); err == nil {
+			t.Error("expected error for rule already registered")
+		}
+	})
+
+	tests := []struct {
+		name    string
+		rule    string
+		value   string
+		wantRes string
+		wantErr error
+	}{
+		{name: "check ok", rule: "test-sku", value: "  SKU-123 ", wantRes: "SKU-123"},
+		{name: "check fail", rule: "test-sku", value: "123", wantErr: errNotSKU},
+		{name: "transform with parameter", rule: "test-prefix=(ab-)", value: " 12 ", wantRes: "ab-12"},
+		{name: "transform before length check", rule: "test-prefix=abc,max=4", value: "12", wantErr: ErrTooLong},
+		{name: "multiple rules in rule order", rule: "test-prefix=sku-,test-upper", value: "1", wantRes: "SKU-1"},
+		{name: "multiple rules in reverse order", rule: "test-upper,test-prefix=sku-", value: "1", wantRes: "sku-1"},
+		{name: "invalid parameter", rule: "test-prefix", value: "1", wantErr: ErrInvalidParameter},
+		{name: "panic in rule", rule: "test-panic", value: "1", wantErr: ErrRulePanic},
+		{name: "panic in factory", rule: "test-panic=factory", value: "1", wantErr: ErrRulePanic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := Validate(tt.value, tt.rule)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("Validate() = %v, want %v", gotRes, tt.wantRes)
+			}
+		})
+	}
+
+	t.Run("error contains rule name", func(t *testing.T) {
+		_, err := Validate([]string{"SKU-1", "foo"}, "value=(test-sku)")
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("error is not a ValidationError: %v", err)
+		}
+		if vErr.Rule != "test-sku" || vErr.Path != "[1]" {
+			t.Errorf("got Rule = %q, Path = %q", vErr.Rule, vErr.Path)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/cases"
+)
+
+// stringSet is a set of allowed values, used by the "oneof" and "in" rules
+type stringSet struct {
+	// Values in the set
+	values map[string]struct{}
+	// Values in the set, keyed by their case-folded form
+	folded map[string]string
+}
+
+var (
+	customSets     = map[string]*stringSet{}
+	customSetsLock sync.RWMutex
+)
+
+// RegisterSet registers a named set of allowed values, which can be referenced in rules for strings as `in=name`.
+// For example, after registering a set called "countries", the rule `in=countries` requires values to be one of the values in the set.
+// Values are matched exactly after the string has been sanitized, unless the "ignorecase" rule is set too.
+// The name must not be empty, the list of values must not be empty, and each set can be registered only once.
+// Registering a set purges all cached validators.
+func RegisterSet(name string, values []string) error {
+	if name == "" {
+		return errors.New("name must not be empty")
+	}
+	if len(values) == 0 {
+		return errors.New("list of values must not be empty")
+	}
+
+	set := newStringSet(values)
+
+	customSetsLock.Lock()
+	defer customSetsLock.Unlock()
+	if _, ok := customSets[name]; ok {
+		return fmt.Errorf("set '%s' is already registered", name)
+	}
+	customSets[name] = set
+
+	// Cached validators may have been created before the set was registered
+	validators.purge()
+
+	return nil
+}
+
+// getSet returns the set with the given name, if it was registered with RegisterSet
+func getSet(name string) (*stringSet, bool) {
+	customSetsLock.RLock()
+	set, ok := customSets[name]
+	customSetsLock.RUnlock()
+	return set, ok
+}
+
+// newStringSet returns a new stringSet with the given values
+func newStringSet(values []string) *stringSet {
+	set := &stringSet{
+		values: make(map[string]struct{}, len(values)),
+	}
+	for _, v := range values {
+		set.values[v] = struct{}{}
+	}
+
+	// Case-folded values are pre-computed so they can be accessed concurrently without locking
+	set.folded = make(map[string]string, len(values))
+	for _, v := range values {
+		k := cases.Fold().String(v)
+		// If multiple values have the same folded form, the first one wins
+		if _, ok := set.folded[k]; !ok {
+			set.folded[k] = v
+		}
+	}
+
+	return set
+}
+
+// lookup returns the value in the set that matches val
+// If ignoreCase is true, values are compared after case-folding, and the value returned is the one in the set
+func (s *stringSet) lookup(val string, ignoreCase bool) (string, bool) {
+	if _, ok := s.values[val]; ok {
+		return val, true
+	}
+	if !ignoreCase {
+		return "", false
+	}
+	res, ok := s.folded[cases.Fold().String(val)]
+	return res, ok
+}
@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterSet(t *testing.T) {
+	// Remove the registered set when the test ends, so it can be run multiple times
+	t.Cleanup(func() {
+		customSetsLock.Lock()
+		delete(customSets, "test-countries")
+		customSetsLock.Unlock()
+		validators.purge()
+	})
+
+	// Names are prefixed with "test-" to avoid conflicts with other tests
+	err := RegisterSet("test-countries", []string{"IT", "US", "DE"})
+	if err != nil {
+		t.Fatalf("RegisterSet error = %v", err)
+	}
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		if err := RegisterSet("", []string{"a"}); err == nil {
+			t.Error("expected error for empty name")
+		}
+		if err := RegisterSet("test-empty", nil); err == nil {
+			t.Error("expected error for empty list")
+		}
+		if err := RegisterSet("test-countries", []string{"a"}); err == nil {
+			t.Error("expected error for set already registered")
+		}
+	})
+
+	tests := []struct {
+		name    string
+		rule    string
+		value   any
+		wantRes any
+		wantErr error
+	}{
+		{name: "value in set", rule: "in=test-countries", value: " IT ", wantRes: "IT"},
+		{name: "value not in set", rule: "in=test-countries", value: "it", wantErr: ErrNotAllowed},
+		{name: "ignore case", rule: "in=test-countries,ignorecase", value: "it", wantRes: "IT"},
+		{name: "unknown set", rule: "in=test-nope", value: "IT", wantErr: ErrInvalidParameter},
+		{name: "slice values", rule: "value=(in=test-countries,ignorecase)", value: []string{"us", "De"}, wantRes: []string{"US", "DE"}},
+		{name: "slice values fail", rule: "value=(in=test-countries)", value: []string{"US", "FR"}, wantErr: ErrNotAllowed},
+		{name: "map keys", rule: "key=(in=test-countries)", value: map[string]string{"IT": "Rome"}, wantRes: map[string]string{"IT": "Rome"}},
+		{name: "map keys fail", rule: "key=(in=test-countries)", value: map[string]string{"FR": "Paris"}, wantErr: ErrNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := ValidateAny(tt.value, tt.rule)
+			if !errors.Is(err, tt.wantErr) || (err != nil && tt.wantErr == nil) {
+				t.Errorf("ValidateAny() error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("ValidateAny() = %v, want %v", gotRes, tt.wantRes)
+			}
+		})
+	}
+}
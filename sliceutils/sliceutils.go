@@ -29,3 +29,48 @@ func RemoveDuplicatesInSortedSlice[T constraints.Ordered](s []T) []T {
 
 	return s[:n]
 }
+
+// SortSliceFunc sorts a slice of any value, using the less function to compare values
+// The sort is stable
+func SortSliceFunc[T any](s []T, less func(a, b T) bool) {
+	sort.SliceStable(s, func(i, j int) bool {
+		return less(s[i], s[j])
+	})
+}
+
+// RemoveDuplicatesFunc removes duplicates from a slice, using the key function to compare values
+// Values with the same key are duplicates; if keepLast is false, the first value with each key is kept, otherwise the last one is
+// The order of the values that are kept is preserved
+func RemoveDuplicatesFunc[T any, K comparable](s []T, key func(T) K, keepLast bool) []T {
+	if len(s) < 2 {
+		return s
+	}
+
+	seen := make(map[K]struct{}, len(s))
+	if !keepLast {
+		n := 0
+		for i := 0; i < len(s); i++ {
+			k := key(s[i])
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			s[n] = s[i]
+			n++
+		}
+		return s[:n]
+	}
+
+	// Iterate backwards to keep the last value with each key, then move the values to the beginning of the slice
+	n := len(s)
+	for i := len(s) - 1; i >= 0; i-- {
+		k := key(s[i])
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		n--
+		s[n] = s[i]
+	}
+	return s[:copy(s, s[n:])]
+}
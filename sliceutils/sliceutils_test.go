@@ -0,0 +1,38 @@
+package sliceutils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRemoveDuplicatesFunc(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    []string
+		keepLast bool
+		wantRes  []string
+	}{
+		{name: "nil", value: nil, wantRes: nil},
+		{name: "empty", value: []string{}, wantRes: []string{}},
+		{name: "single value", value: []string{"a"}, wantRes: []string{"a"}},
+		{name: "single value keep last", value: []string{"a"}, keepLast: true, wantRes: []string{"a"}},
+		{name: "no duplicates", value: []string{"c", "a", "b"}, wantRes: []string{"c", "a", "b"}},
+		{name: "no duplicates keep last", value: []string{"c", "a", "b"}, keepLast: true, wantRes: []string{"c", "a", "b"}},
+		{name: "keep first", value: []string{"b", "A", "a", "B", "c"}, wantRes: []string{"b", "A", "c"}},
+		{name: "keep last", value: []string{"b", "A", "a", "B", "c"}, keepLast: true, wantRes: []string{"a", "B", "c"}},
+		{name: "keep first preserves order", value: []string{"c", "b", "C", "a", "B"}, wantRes: []string{"c", "b", "a"}},
+		{name: "keep last preserves order", value: []string{"c", "b", "C", "a", "B"}, keepLast: true, wantRes: []string{"C", "a", "B"}},
+		{name: "all duplicates", value: []string{"a", "A", "a"}, wantRes: []string{"a"}},
+		{name: "all duplicates keep last", value: []string{"a", "A", "a"}, keepLast: true, wantRes: []string{"a"}},
+		{name: "adjacent duplicates keep last", value: []string{"x", "X", "y", "Y", "Y"}, keepLast: true, wantRes: []string{"X", "Y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes := RemoveDuplicatesFunc(tt.value, strings.ToLower, tt.keepLast)
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("RemoveDuplicatesFunc() = %v, want %v", gotRes, tt.wantRes)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TypeValidatorFunc is the function that validates and sanitizes a value of a type registered with RegisterType.
+type TypeValidatorFunc[T any] func(val T) (T, error)
+
+// TypeValidatorFactory is a function that returns a TypeValidatorFunc for a type registered with RegisterType.
+// It receives the full rule string, and it can return an error if the rule is invalid.
+// It's invoked once when a validator is created, and the result is cached.
+type TypeValidatorFactory[T any] func(rule string) (TypeValidatorFunc[T], error)
+
+// customType contains the validator factories for a type registered with RegisterType
+type customType struct {
+	// Factory for typed validators, of type `func(rule string) (validator[T], error)`
+	typed any
+	// Factory for validators operating on reflect.Value objects
+	reflect func(rule string) (validator[reflect.Value], error)
+}
+
+var (
+	customTypes     = map[reflect.Type]customType{}
+	customTypesLock sync.RWMutex
+)
+
+// RegisterType registers a validator for values of type T, which is used by Validate, ValidateAny, ValidateStruct, and when T is the type of values in slices and maps.
+// This allows validating user-defined types, such as `type Email string`, arrays, or structs.
+// Validators for registered types are invoked for zero values too.
+// Types that are natively supported (`string`, integers, floats, `[]string`, and `map[string]string`) and interfaces can't be registered, and each type can be registered only once.
+// Registering a type purges all cached validators.
+func RegisterType[T any](factory TypeValidatorFactory[T]) error {
+	if factory == nil {
+		return errors.New("factory must not be nil")
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Interface {
+		return fmt.Errorf("type %s is an interface", t)
+	}
+	if t == basicTypes[t.Kind()] || t == reflect.TypeOf([]string(nil)) || t == reflect.TypeOf(map[string]string(nil)) {
+		return fmt.Errorf("type %s is natively supported", t)
+	}
+
+	customTypesLock.Lock()
+	defer customTypesLock.Unlock()
+	if _, ok := customTypes[t]; ok {
+		return fmt.Errorf("type %s is already registered", t)
+	}
+
+	typed := func(rule string) (validator[T], error) {
+		fn, err := callTypeValidatorFactory(t, rule, factory)
+		if err != nil {
+			return nil, err
+		}
+		return safeTypeValidatorFunc(t, fn), nil
+	}
+	customTypes[t] = customType{
+		typed: typed,
+		reflect: func(rule string) (validator[reflect.Value], error) {
+			f, err := typed(rule)
+			if err != nil {
+				return nil, err
+			}
+			return func(v reflect.Value) (reflect.Value, error) {
+				res, err := f(v.Interface().(T))
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				return reflect.ValueOf(&res).Elem(), nil
+			}, nil
+		},
+	}
+
+	// Cached validators may have been created before the type was registered
+	validators.purge()
+
+	return nil
+}
+
+// getCustomType returns the validator factories for t, if the type was registered with RegisterType
+func getCustomType(t reflect.Type) (customType, bool) {
+	customTypesLock.RLock()
+	ct, ok := customTypes[t]
+	customTypesLock.RUnlock()
+	return ct, ok
+}
+
+// callTypeValidatorFactory invokes the factory for a registered type, recovering from panics
+func callTypeValidatorFactory[T any](t reflect.Type, rule string, factory TypeValidatorFactory[T]) (fn TypeValidatorFunc[T], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newValidationError(ErrRulePanic, "", rule, -1, "factory for type %s panicked: %v", t, r)
+		}
+	}()
+
+	fn, err = factory(rule)
+	if err != nil {
+		return nil, newValidationError(ErrInvalidParameter, "", rule, -1, "rule for type %s is invalid: %v", t, err)
+	}
+	if fn == nil {
+		return nil, newValidationError(ErrInvalidParameter, "", rule, -1, "factory for type %s returned a nil function", t)
+	}
+	return fn, nil
+}
+
+// safeTypeValidatorFunc wraps the validator for a registered type so it recovers from panics and returns ValidationError objects
+func safeTypeValidatorFunc[T any](t reflect.Type, fn TypeValidatorFunc[T]) validator[T] {
+	return func(val T) (res T, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				res = zero
+				err = newValidationError(ErrRulePanic, "", "", -1, "validator for type %s panicked: %v", t, r)
+			}
+		}()
+
+		res, err = fn(val)
+		if err != nil {
+			var zero T
+			var vErr *ValidationError
+			if errors.As(err, &vErr) {
+				return zero, err
+			}
+			return zero, newValidationError(err, "", "", -1, "%s", err.Error())
+		}
+		return res, nil
+	}
+}
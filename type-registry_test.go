@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Types used in TestRegisterType
+type (
+	testEmail string
+	testUUID  [4]byte
+	testMoney struct {
+		Amount   int
+		Currency string
+	}
+	testPanicky struct{}
+)
+
+func TestRegisterType(t *testing.T) {
+	errInvalidEmail := errors.New("value is not a valid email")
+
+	// Remove the registered types when the test ends, so it can be run multiple times
+	t.Cleanup(func() {
+		customTypesLock.Lock()
+		for _, typ := range []reflect.Type{
+			reflect.TypeOf(testEmail("")),
+			reflect.TypeOf(testUUID{}),
+			reflect.TypeOf(testMoney{}),
+			reflect.TypeOf(testPanicky{}),
+		} {
+			delete(customTypes, typ)
+		}
+		customTypesLock.Unlock()
+		validators.purge()
+	})
+
+	err := RegisterType(func(rule string) (TypeValidatorFunc[testEmail], error) {
+		lower := rule == "lower"
+		if rule != "" && !lower {
+			return nil, errors.New("unknown rule")
+		}
+		return func(val testEmail) (testEmail, error) {
+			str := strings.TrimSpace(string(val))
+			if !strings.Contains(str, "@") {
+				return "", errInvalidEmail
+			}
+			if lower {
+				str = strings.ToLower(str)
+			}
+			return testEmail(str), nil
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterType error = %v", err)
+	}
+	err = RegisterType(func(rule string) (TypeValidatorFunc[testUUID], error) {
+		return func(val testUUID) (testUUID, error) {
+			if val == (testUUID{}) {
+				return val, errors.New("UUID is empty")
+			}
+			return val, nil
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterType error = %v", err)
+	}
+	err = RegisterType(func(rule string) (TypeValidatorFunc[testMoney], error) {
+		return func(val testMoney) (testMoney, error) {
+			if val.Amount < 0 {
+				return val, errors.New("amount is negative")
+			}
+			val.Currency = strings.ToUpper(val.Currency)
+			return val, nil
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterType error = %v", err)
+	}
+	err = RegisterType(func(rule string) (TypeValidatorFunc[testPanicky], error) {
+		if rule == "factory" {
+			panic("oh no")
+		}
+		return func(val testPanicky) (testPanicky, error) {
+			panic("oh no")
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterType error = %v", err)
+	}
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		if err := RegisterType[testEmail](nil); err == nil {
+			t.Error("expected error for nil factory")
+		}
+		if err := RegisterType(func(rule string) (TypeValidatorFunc[testEmail], error) { return nil, nil }); err == nil {
+			t.Error("expected error for type already registered")
+		}
+		if err := RegisterType(func(rule string) (TypeValidatorFunc[string], error) { return nil, nil }); err == nil {
+			t.Error("expected error for natively-supported type")
+		}
+		if err := RegisterType(func(rule string) (TypeValidatorFunc[error], error) { return nil, nil }); err == nil {
+			t.Error("expected error for interface")
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		email, err := Validate(testEmail(" Foo@Example.com "), "lower")
+		if err != nil || email != "foo@example.com" {
+			t.Errorf("Validate() = %v, %v", email, err)
+		}
+		_, err = Validate(testEmail("foo"), "")
+		if !errors.Is(err, errInvalidEmail) {
+			t.Errorf("Validate() error = %v, want %v", err, errInvalidEmail)
+		}
+		_, err = Validate(testEmail("foo@example.com"), "invalid")
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("Validate() error = %v, want %v", err, ErrInvalidParameter)
+		}
+
+		// Validators for registered types are invoked for zero values too
+		_, err = Validate(testUUID{}, "")
+		if err == nil {
+			t.Error("Validate() expected an error for zero UUID")
+		}
+		money, err := Validate(testMoney{Amount: 10, Currency: "eur"}, "")
+		if err != nil || money != (testMoney{Amount: 10, Currency: "EUR"}) {
+			t.Errorf("Validate() = %v, %v", money, err)
+		}
+	})
+
+	t.Run("ValidateAny", func(t *testing.T) {
+		res, err := ValidateAny(testEmail("A@B"), "lower")
+		if err != nil || res != testEmail("a@b") {
+			t.Errorf("ValidateAny() = %v, %v", res, err)
+		}
+		uuid := testUUID{1, 2, 3, 4}
+		res, err = ValidateAny(&uuid, "")
+		if err != nil {
+			t.Errorf("ValidateAny() error = %v", err)
+		} else if ptr, ok := res.(*testUUID); !ok || *ptr != uuid {
+			t.Errorf("ValidateAny() = %v", res)
+		}
+	})
+
+	t.Run("slices and maps", func(t *testing.T) {
+		emails, err := Validate([]testEmail{" C@D ", "A@B"}, "value=(lower),sort")
+		if err != nil || !reflect.DeepEqual(emails, []testEmail{"a@b", "c@d"}) {
+			t.Errorf("Validate() = %v, %v", emails, err)
+		}
+		_, err = Validate([]testEmail{"a@b", "c"}, "")
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || vErr.Path != "[1]" || !errors.Is(err, errInvalidEmail) {
+			t.Errorf("Validate() error = %v", err)
+		}
+		money, err := Validate(map[string][]testMoney{"a": {{Amount: 1, Currency: "usd"}}}, "")
+		if err != nil || !reflect.DeepEqual(money, map[string][]testMoney{"a": {{Amount: 1, Currency: "USD"}}}) {
+			t.Errorf("Validate() = %v, %v", money, err)
+		}
+	})
+
+	t.Run("ValidateStruct", func(t *testing.T) {
+		val := struct {
+			Email   testEmail   `validate:"lower"`
+			Emails  []testEmail `validate:"value=(lower)"`
+			Price   testMoney   `validate:""`
+			NoRule  testMoney
+			Ignored testUUID `validate:"-"`
+		}{
+			Email:  "A@B",
+			Emails: []testEmail{"C@D"},
+			Price:  testMoney{Amount: 1, Currency: "chf"},
+			NoRule: testMoney{Amount: 1, Currency: "chf"},
+		}
+		err := ValidateStruct(&val)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+		if val.Email != "a@b" || val.Emails[0] != "c@d" || val.Price.Currency != "CHF" || val.NoRule.Currency != "chf" {
+			t.Errorf("ValidateStruct() = %v", val)
+		}
+	})
+
+	t.Run("panics are recovered", func(t *testing.T) {
+		_, err := Validate(testPanicky{}, "")
+		if !errors.Is(err, ErrRulePanic) {
+			t.Errorf("Validate() error = %v, want %v", err, ErrRulePanic)
+		}
+		_, err = Validate(testPanicky{}, "factory")
+		if !errors.Is(err, ErrRulePanic) {
+			t.Errorf("Validate() error = %v, want %v", err, ErrRulePanic)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package validator
+
+import (
+	"unicode"
+
+	"golang.org/x/text/secure/bidirule"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// isBidiControl returns true if r is a bidirectional control character: the Arabic Letter Mark, the implicit directional marks, and the explicit embedding, override, and isolate characters
+// These characters can be used to make text display in an order that is different from the logical one (as in "Trojan Source" attacks)
+func isBidiControl(r rune) bool {
+	switch {
+	case r == 0x061c, r == 0x200e, r == 0x200f:
+		// ALM, LRM, RLM
+		return true
+	case r >= 0x202a && r <= 0x202e:
+		// LRE, RLE, PDF, LRO, RLO
+		return true
+	case r >= 0x2066 && r <= 0x2069:
+		// LRI, RLI, FSI, PDI
+		return true
+	default:
+		return false
+	}
+}
+
+// firstBidiControl returns the first bidirectional control character in s and its position (1-based, counted in characters), or -1 if s doesn't contain any
+func firstBidiControl(s string) (rune, int) {
+	pos := 0
+	for _, r := range s {
+		pos++
+		if isBidiControl(r) {
+			return r, pos
+		}
+	}
+	return 0, -1
+}
+
+// checkBidiRule checks that s satisfies the Bidi Rule from RFC 5893
+// Each word (separated by whitespace) is checked as a label; as in the RFC, the rule applies only if the string contains right-to-left characters
+// Words without letters, such as numbers, take the right-to-left direction of the surrounding text instead of being checked as labels
+// If the check fails, it returns the offending character, its position (1-based, counted in characters), and the reason
+func checkBidiRule(s string) (r rune, pos int, reason string) {
+	if bidirule.DirectionString(s) != bidi.RightToLeft {
+		return 0, -1, ""
+	}
+
+	// Split the string in words, keeping track of the position of each one
+	wordStart := -1
+	wordPos := 0
+	pos = 0
+	for i, c := range s {
+		pos++
+		if !unicode.IsSpace(c) {
+			if wordStart < 0 {
+				wordStart = i
+				wordPos = pos
+			}
+			continue
+		}
+		if wordStart >= 0 {
+			if r, p, reason := checkBidiWord(s[wordStart:i]); p > 0 {
+				return r, wordPos + p - 1, reason
+			}
+			wordStart = -1
+		}
+	}
+	if wordStart >= 0 {
+		if r, p, reason := checkBidiWord(s[wordStart:]); p > 0 {
+			return r, wordPos + p - 1, reason
+		}
+	}
+
+	return 0, -1, ""
+}
+
+// checkBidiWord checks a word of a string that contains right-to-left text
+func checkBidiWord(word string) (rune, int, string) {
+	for _, r := range word {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.L, bidi.R, bidi.AL:
+			return checkBidiLabel(word)
+		}
+	}
+	return checkBidiNeutralWord(word)
+}
+
+// checkBidiNeutralWord checks a word without letters, which is part of the right-to-left text around it
+// Only digits, separators, punctuation, and combining marks are allowed, and European and Arabic digits can't be mixed (as in rule 4)
+func checkBidiNeutralWord(word string) (rune, int, string) {
+	var hasEN, hasAN bool
+	pos := 0
+	for _, r := range word {
+		pos++
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.EN:
+			hasEN = true
+		case bidi.AN:
+			hasAN = true
+		case bidi.ES, bidi.CS, bidi.ET, bidi.ON, bidi.BN, bidi.NSM:
+			// Allowed
+		default:
+			return r, pos, "is not allowed in a right-to-left word"
+		}
+		if hasEN && hasAN {
+			return r, pos, "must not be mixed with digits of a different type"
+		}
+	}
+	return 0, -1, ""
+}
+
+// checkBidiLabel checks that label satisfies the Bidi Rule using bidirule.ValidString
+// If it doesn't, it returns the offending character, its position in the label, and the reason
+func checkBidiLabel(label string) (rune, int, string) {
+	if bidirule.ValidString(label) {
+		return 0, -1, ""
+	}
+
+	rtl := bidirule.DirectionString(label) == bidi.RightToLeft
+	var (
+		hasEN, hasAN bool
+		last         rune
+		lastClass    bidi.Class
+		lastPos      int
+		pos          int
+	)
+	for _, r := range label {
+		pos++
+		p, _ := bidi.LookupRune(r)
+		class := p.Class()
+
+		// Rule 1: the first character must be a letter with a strong direction
+		if pos == 1 && class != bidi.L && class != bidi.R && class != bidi.AL {
+			return r, pos, "must not be at the beginning of a word"
+		}
+
+		switch class {
+		case bidi.EN, bidi.ES, bidi.CS, bidi.ET, bidi.ON, bidi.BN, bidi.NSM:
+			// Allowed in both directions
+		case bidi.R, bidi.AL, bidi.AN:
+			// Rule 2: allowed in right-to-left words only, but a word that contains one of these is always right-to-left
+		case bidi.L:
+			// Rule 5: allowed in left-to-right words only
+			if rtl {
+				return r, pos, "is not allowed in a right-to-left word"
+			}
+		default:
+			if rtl {
+				return r, pos, "is not allowed in a right-to-left word"
+			}
+			return r, pos, "is not allowed in a left-to-right word"
+		}
+
+		// Rule 4: right-to-left words can't contain both European and Arabic digits
+		if rtl {
+			hasEN = hasEN || class == bidi.EN
+			hasAN = hasAN || class == bidi.AN
+			if hasEN && hasAN {
+				return r, pos, "must not be mixed with digits of a different type"
+			}
+		}
+
+		if class != bidi.NSM {
+			last, lastClass, lastPos = r, class, pos
+		}
+	}
+
+	// Rules 3 and 6: the word must end with a letter or a digit, optionally followed by combining marks
+	switch {
+	case rtl && lastClass != bidi.R && lastClass != bidi.AL && lastClass != bidi.EN && lastClass != bidi.AN,
+		!rtl && lastClass != bidi.L && lastClass != bidi.EN:
+		return last, lastPos, "must not be at the end of a word"
+	}
+
+	// Should not happen, but report the first character of the word if we couldn't find the reason
+	first := []rune(label)[0]
+	return first, 1, "starts a word that does not satisfy the bidi rule"
+}
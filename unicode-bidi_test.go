@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"testing"
+)
+
+func Test_checkBidiRule(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantRune rune
+		wantPos  int
+	}{
+		{value: "hello world!", wantPos: -1},
+		{value: "דוד כהן", wantPos: -1},
+		{value: "محمد علي", wantPos: -1},
+		{value: "David דוד", wantPos: -1},
+		{value: "דוד123", wantPos: -1},
+		{value: "דוד 123", wantPos: -1},
+		{value: "שלום 5", wantPos: -1},
+		{value: "محمد ١٢٣", wantPos: -1},
+		{value: "محمد ١2", wantRune: '2', wantPos: 7},
+		{value: "דוד 1a", wantRune: '1', wantPos: 5},
+		{value: "שלום!", wantRune: '!', wantPos: 5},
+		{value: "abc!", wantPos: -1},
+		{value: "abc! דוד", wantRune: '!', wantPos: 4},
+		{value: "דודa", wantRune: 'a', wantPos: 4},
+		{value: "1דוד", wantRune: '1', wantPos: 1},
+		{value: "محمد م١2", wantRune: '2', wantPos: 8},
+		{value: "כהן דוד!", wantRune: '!', wantPos: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			r, pos, _ := checkBidiRule(tt.value)
+			if pos != tt.wantPos || r != tt.wantRune {
+				t.Errorf("checkBidiRule() = %q at %d, want %q at %d", r, pos, tt.wantRune, tt.wantPos)
+			}
+		})
+	}
+}
+
+func Test_firstBidiControl(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantRune rune
+		wantPos  int
+	}{
+		{value: "hello", wantPos: -1},
+		{value: "דוד", wantPos: -1},
+		{value: "abc‮def", wantRune: 0x202e, wantPos: 4},
+		{value: "⁦x⁩", wantRune: 0x2066, wantPos: 1},
+		{value: "a‏b", wantRune: 0x200f, wantPos: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			r, pos := firstBidiControl(tt.value)
+			if pos != tt.wantPos || r != tt.wantRune {
+				t.Errorf("firstBidiControl() = %U at %d, want %U at %d", r, pos, tt.wantRune, tt.wantPos)
+			}
+		})
+	}
+}
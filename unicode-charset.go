@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Names of the scripts in unicode.Scripts, in lowercase, so they can be matched ignoring case
+var scriptNamesFold = func() map[string]string {
+	res := make(map[string]string, len(unicode.Scripts))
+	for name := range unicode.Scripts {
+		res[strings.ToLower(name)] = name
+	}
+	return res
+}()
+
+// parseCharsList parses the value of the "chars" rule, a list of Unicode general categories (such as "L" or "Nd") and individual characters
+// It returns a function that reports whether a character is in the list
+func parseCharsList(val string) (func(r rune) bool, error) {
+	items, err := parseList(val, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*unicode.RangeTable, 0, len(items))
+	literals := make(map[rune]struct{})
+	for _, item := range items {
+		if table, ok := unicode.Categories[item]; ok {
+			tables = append(tables, table)
+			continue
+		}
+		if utf8.RuneCountInString(item) == 1 {
+			r, _ := utf8.DecodeRuneInString(item)
+			literals[r] = struct{}{}
+			continue
+		}
+		return nil, fmt.Errorf("'%s' is not a Unicode category or a single character", item)
+	}
+
+	return func(r rune) bool {
+		if _, ok := literals[r]; ok {
+			return true
+		}
+		return unicode.In(r, tables...)
+	}, nil
+}
+
+// parseScriptsList parses the value of the "scripts" rule, a list of Unicode script names (such as "Latin" or "Han")
+// It returns a function that reports whether a character is in one of the scripts; characters in the Common and Inherited scripts (such as digits, punctuation, and combining marks) are always allowed
+func parseScriptsList(val string) (func(r rune) bool, error) {
+	items, err := parseList(val, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []*unicode.RangeTable{unicode.Common, unicode.Inherited}
+	for _, item := range items {
+		name, ok := scriptNamesFold[strings.ToLower(item)]
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not a Unicode script", item)
+		}
+		tables = append(tables, unicode.Scripts[name])
+	}
+
+	return func(r rune) bool {
+		return unicode.In(r, tables...)
+	}, nil
+}
+
+// firstDisallowedRune returns the first character in s for which allowed returns false, and its position (1-based, counted in characters), or -1 if all characters are allowed
+func firstDisallowedRune(s string, allowed func(r rune) bool) (rune, int) {
+	pos := 0
+	for _, r := range s {
+		pos++
+		if !allowed(r) {
+			return r, pos
+		}
+	}
+	return 0, -1
+}
@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"testing"
+)
+
+func Test_parseCharsList(t *testing.T) {
+	tests := []struct {
+		list    string
+		allowed string
+		denied  string
+		wantErr bool
+	}{
+		{list: "L,Nd,Zs,-", allowed: "aZéж日1٣ -", denied: "_.!\t\U0001F600"},
+		{list: "Lu", allowed: "AÉЖ", denied: "aé1"},
+		{list: `",",'.'`, allowed: ",.", denied: "a;"},
+		{list: `\,`, allowed: ",", denied: "a"},
+		{list: "Letters", wantErr: true},
+		{list: "L,,Nd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			allowed, err := parseCharsList(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCharsList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for _, r := range tt.allowed {
+				if !allowed(r) {
+					t.Errorf("character %U '%c' is not allowed", r, r)
+				}
+			}
+			for _, r := range tt.denied {
+				if allowed(r) {
+					t.Errorf("character %U '%c' is allowed", r, r)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseScriptsList(t *testing.T) {
+	tests := []struct {
+		list    string
+		allowed string
+		denied  string
+		wantErr bool
+	}{
+		{list: "Latin,Han", allowed: "abcé日本 1-́", denied: "жαあ"},
+		{list: "cyrillic", allowed: "жя 2", denied: "a"},
+		{list: "Klingon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			allowed, err := parseScriptsList(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseScriptsList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for _, r := range tt.allowed {
+				if !allowed(r) {
+					t.Errorf("character %U '%c' is not allowed", r, r)
+				}
+			}
+			for _, r := range tt.denied {
+				if allowed(r) {
+					t.Errorf("character %U '%c' is allowed", r, r)
+				}
+			}
+		})
+	}
+}
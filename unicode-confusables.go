@@ -0,0 +1,390 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Prototypes for characters that can be confused with other characters, based on confusables.txt from UTS #39
+// This includes the characters that are most commonly used for spoofing Latin text: letters from the Cyrillic, Greek, Armenian, and Latin scripts, digits, and punctuation
+// Characters that are not in this table but have a compatibility decomposition (such as fullwidth and mathematical letters) are mapped to the decomposition
+var confusablesTable = map[rune]string{
+	// ASCII
+	'0': "O",
+	'1': "l",
+	'I': "l",
+	'|': "l",
+	'm': "rn",
+
+	// Latin
+	'ı': "i",
+	'ɩ': "i",
+	'ɑ': "a",
+	'ɡ': "g",
+	'ɪ': "i",
+	'ʏ': "y",
+	'ʟ': "L",
+	'ǀ': "l",
+	'ℓ': "l",
+	'ſ': "f",
+	'ꜱ': "s",
+	'ᴄ': "c",
+	'ᴏ': "o",
+	'ᴠ': "v",
+	'ᴡ': "w",
+	'ᴢ': "z",
+
+	// Cyrillic
+	'а': "a",
+	'А': "A",
+	'В': "B",
+	'в': "ʙ",
+	'Ь': "b",
+	'б': "6",
+	'с': "c",
+	'С': "C",
+	'ԁ': "d",
+	'е': "e",
+	'Е': "E",
+	'һ': "h",
+	'Һ': "h",
+	'Н': "H",
+	'н': "ʜ",
+	'і': "i",
+	'І': "l",
+	'Ӏ': "l",
+	'ӏ': "l",
+	'ј': "j",
+	'Ј': "J",
+	'К': "K",
+	'М': "M",
+	'о': "o",
+	'О': "O",
+	'р': "p",
+	'Р': "P",
+	'ԛ': "q",
+	'Ԛ': "Q",
+	'ѕ': "s",
+	'Ѕ': "S",
+	'Т': "T",
+	'т': "ᴛ",
+	'ԝ': "w",
+	'Ԝ': "W",
+	'х': "x",
+	'Х': "X",
+	'у': "y",
+	'У': "Y",
+	'ү': "y",
+	'Ү': "Y",
+	'З': "3",
+	'ɜ': "з",
+
+	// Greek
+	'α': "a",
+	'Α': "A",
+	'Β': "B",
+	'ϲ': "c",
+	'Ϲ': "C",
+	'Ε': "E",
+	'Η': "H",
+	'ι': "i",
+	'Ι': "l",
+	'ϳ': "j",
+	'Κ': "K",
+	'κ': "ĸ",
+	'Μ': "M",
+	'Ν': "N",
+	'ν': "v",
+	'ο': "o",
+	'Ο': "O",
+	'ρ': "p",
+	'Ρ': "P",
+	'Τ': "T",
+	'υ': "u",
+	'γ': "y",
+	'Υ': "Y",
+	'Χ': "X",
+	'Ζ': "Z",
+
+	// Armenian
+	'Լ': "L",
+	'հ': "h",
+	'ո': "n",
+	'Օ': "O",
+	'օ': "o",
+	'զ': "q",
+	'Ս': "U",
+	'ս': "u",
+	'Տ': "S",
+
+	// Punctuation
+	'‐': "-",
+	'‑': "-",
+	'‒': "-",
+	'–': "-",
+	'−': "-",
+	'‘': "'",
+	'’': "'",
+	'‛': "'",
+	'′': "'",
+	'ʹ': "'",
+	'ʻ': "'",
+	'ʼ': "'",
+	'“': "''",
+	'”': "''",
+	'″': "''",
+	'"': "''",
+	'٫': ",",
+	'‚': ",",
+	'․': ".",
+	'·': ".",
+	'꞉': ":",
+	'∶': ":",
+	'／': "/",
+	'⁄': "/",
+	'∕': "/",
+}
+
+// Skeleton returns the skeleton of s, as defined by UTS #39, which can be used to check if two strings are confusable.
+// Two strings are confusable if their skeletons are equal: for example, the skeleton of "раураl" (with Cyrillic letters) is the same as the skeleton of "paypal".
+// Skeletons are meant to be compared with each other and they should not be displayed to users, as they are not necessarily readable.
+//
+// This implementation includes the prototypes for the characters that are most commonly used for spoofing Latin text, and it's not a complete implementation of the confusables data from UTS #39.
+func Skeleton(s string) string {
+	s = norm.NFD.String(s)
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		if isDefaultIgnorable(r) {
+			continue
+		}
+		sb.WriteString(confusablePrototype(r))
+	}
+
+	return norm.NFD.String(sb.String())
+}
+
+// confusablePrototype returns the prototype of r
+func confusablePrototype(r rune) string {
+	if p, ok := confusablesTable[r]; ok {
+		return p
+	}
+	if r < utf8.RuneSelf {
+		return string(r)
+	}
+
+	// Characters with a compatibility decomposition, such as fullwidth letters, are confusable with their decomposition
+	d := norm.NFKD.String(string(r))
+	if d == string(r) || d == "" {
+		return d
+	}
+	var sb strings.Builder
+	for _, c := range d {
+		if p, ok := confusablesTable[c]; ok {
+			sb.WriteString(p)
+		} else {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// isDefaultIgnorable returns true if r has the property Default_Ignorable_Code_Point
+func isDefaultIgnorable(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Other_Default_Ignorable_Code_Point, unicode.Variation_Selector):
+		return true
+	case unicode.Is(unicode.Cf, r):
+		// Format characters are ignorable, with some exceptions
+		return !unicode.Is(unicode.Prepended_Concatenation_Mark, r) &&
+			!(r >= 0xfff9 && r <= 0xfffb) &&
+			!(r >= 0x13430 && r <= 0x1343f)
+	default:
+		return false
+	}
+}
+
+// restrictionLevel is a restriction level for identifiers, as defined by UTS #39
+type restrictionLevel int
+
+const (
+	restrictionASCII restrictionLevel = iota
+	restrictionSingleScript
+	restrictionHighlyRestrictive
+	restrictionModeratelyRestrictive
+	restrictionMinimallyRestrictive
+)
+
+// Scripts that are recommended for use in identifiers, from table 5 of UAX #31, excluding Cyrillic and Greek, which are handled separately
+// These are the scripts that can be mixed with Latin in strings with the "moderately restrictive" level
+var moderatelyRestrictiveScripts = map[string]struct{}{
+	"Arabic": {}, "Armenian": {}, "Bengali": {}, "Bopomofo": {}, "Devanagari": {}, "Ethiopic": {}, "Georgian": {},
+	"Gujarati": {}, "Gurmukhi": {}, "Han": {}, "Hangul": {}, "Hebrew": {}, "Hiragana": {}, "Kannada": {}, "Katakana": {},
+	"Khmer": {}, "Lao": {}, "Malayalam": {}, "Myanmar": {}, "Oriya": {}, "Sinhala": {}, "Tamil": {}, "Telugu": {},
+	"Thaana": {}, "Thai": {}, "Tibetan": {},
+}
+
+// Sets of scripts that can be mixed in strings with the "highly restrictive" level
+var highlyRestrictiveScripts = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// Scripts that are checked first when looking up the script of a character, as they're the most common
+var commonScripts = []string{"Latin", "Common", "Inherited", "Cyrillic", "Greek", "Han", "Arabic", "Hebrew", "Hiragana", "Katakana", "Hangul"}
+
+// Sorted names of all scripts, used when the script of a character is not one of the common ones
+var allScripts = func() []string {
+	res := make([]string, 0, len(unicode.Scripts))
+	for name := range unicode.Scripts {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}()
+
+// scriptOf returns the name of the script of r (its Script property), or an empty string if it's unknown
+func scriptOf(r rune) string {
+	for _, name := range commonScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for _, name := range allScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// augmentedScriptSet returns the augmented script set for a script, as defined by UTS #39
+// This allows treating the scripts used to write Japanese, Korean, and Chinese (with Bopomofo) as a single script each
+func augmentedScriptSet(script string) []string {
+	switch script {
+	case "Han":
+		return []string{"Han", "Hanb", "Jpan", "Kore"}
+	case "Hiragana", "Katakana":
+		return []string{script, "Jpan"}
+	case "Hangul":
+		return []string{"Hangul", "Kore"}
+	case "Bopomofo":
+		return []string{"Bopomofo", "Hanb"}
+	default:
+		return []string{script}
+	}
+}
+
+// resolvedScriptSet returns the intersection of the augmented script sets of all scripts
+func resolvedScriptSet(scripts []string) map[string]struct{} {
+	var res map[string]struct{}
+	for _, script := range scripts {
+		set := map[string]struct{}{}
+		for _, s := range augmentedScriptSet(script) {
+			if _, ok := res[s]; ok || res == nil {
+				set[s] = struct{}{}
+			}
+		}
+		res = set
+	}
+	return res
+}
+
+// getRestrictionLevel returns the restriction level of s as defined by UTS #39, and the list of scripts that are used in s (excluding Common and Inherited)
+func getRestrictionLevel(s string) (restrictionLevel, []string) {
+	if isASCII(s) {
+		return restrictionASCII, nil
+	}
+
+	// Collect the scripts used in the string
+	used := map[string]struct{}{}
+	for _, r := range s {
+		script := scriptOf(r)
+		if script == "" || script == "Common" || script == "Inherited" {
+			continue
+		}
+		used[script] = struct{}{}
+	}
+	scripts := make([]string, 0, len(used))
+	for script := range used {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+
+	if len(scripts) <= 1 || len(resolvedScriptSet(scripts)) > 0 {
+		return restrictionSingleScript, scripts
+	}
+
+	for _, allowed := range highlyRestrictiveScripts {
+		if isSubset(scripts, allowed) {
+			return restrictionHighlyRestrictive, scripts
+		}
+	}
+
+	// Latin can be mixed with a single other script, excluding Cyrillic and Greek
+	other := make([]string, 0, len(scripts))
+	for _, script := range scripts {
+		if script == "Latin" {
+			continue
+		}
+		if _, ok := moderatelyRestrictiveScripts[script]; !ok {
+			return restrictionMinimallyRestrictive, scripts
+		}
+		other = append(other, script)
+	}
+	if len(resolvedScriptSet(other)) > 0 {
+		return restrictionModeratelyRestrictive, scripts
+	}
+
+	return restrictionMinimallyRestrictive, scripts
+}
+
+// isSubset returns true if all elements of a are in b
+func isSubset(a []string, b []string) bool {
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// hasMixedNumbers returns true if s contains decimal digits from more than one numbering system, such as "0" and "٠" (Arabic-Indic zero)
+func hasMixedNumbers(s string) bool {
+	var zero rune = -1
+	for _, r := range s {
+		if !unicode.Is(unicode.Nd, r) {
+			continue
+		}
+		z := digitZero(r)
+		if zero == -1 {
+			zero = z
+		} else if z != zero {
+			return true
+		}
+	}
+	return false
+}
+
+// digitZero returns the zero digit of the numbering system of the decimal digit r
+// Digits in each numbering system are encoded as contiguous sequences from 0 to 9, but multiple systems may be adjacent (for example, mathematical digits)
+func digitZero(r rune) rune {
+	start := r
+	for unicode.Is(unicode.Nd, start-1) {
+		start--
+	}
+	return r - (r-start)%10
+}
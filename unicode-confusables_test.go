@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a          string
+		b          string
+		confusable bool
+	}{
+		{a: "paypal", b: "paypal", confusable: true},
+		{a: "раураl", b: "paypal", confusable: true},
+		{a: "аpple", b: "apple", confusable: true},
+		{a: "ΑΒΕ", b: "ABE", confusable: true},
+		{a: "Օօ", b: "Oo", confusable: true},
+		{a: "rnicrosoft", b: "microsoft", confusable: true},
+		{a: "I1l|", b: "llll", confusable: true},
+		{a: "g00gle", b: "gOOgle", confusable: true},
+		{a: "ｐａｙｐａｌ", b: "paypal", confusable: true},
+		{a: "pay​pal", b: "paypal", confusable: true},
+		{a: "pay‐pal", b: "pay-pal", confusable: true},
+		{a: "paypal", b: "Paypal", confusable: false},
+		{a: "café", b: "cafe", confusable: false},
+		{a: "hello", b: "world", confusable: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			skA, skB := Skeleton(tt.a), Skeleton(tt.b)
+			if (skA == skB) != tt.confusable {
+				t.Errorf("Skeleton(%q) = %q, Skeleton(%q) = %q, want confusable %v", tt.a, skA, tt.b, skB, tt.confusable)
+			}
+		})
+	}
+}
+
+func Test_getRestrictionLevel(t *testing.T) {
+	tests := []struct {
+		value string
+		want  restrictionLevel
+	}{
+		{value: "hello world", want: restrictionASCII},
+		{value: "café", want: restrictionSingleScript},
+		{value: "Москва", want: restrictionSingleScript},
+		{value: "Москва 2024!", want: restrictionSingleScript},
+		{value: "東京タワーとひらがな", want: restrictionSingleScript},
+		{value: "한국어 漢字", want: restrictionSingleScript},
+		{value: "Tokyo 東京タワー", want: restrictionHighlyRestrictive},
+		{value: "Seoul 서울", want: restrictionHighlyRestrictive},
+		{value: "Cairo القاهرة", want: restrictionModeratelyRestrictive},
+		{value: "раураl", want: restrictionMinimallyRestrictive},
+		{value: "Αthens", want: restrictionMinimallyRestrictive},
+		{value: "Tel Aviv תל אביב القاهرة", want: restrictionMinimallyRestrictive},
+		{value: "ᏚᎢᎵ abc", want: restrictionMinimallyRestrictive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got, _ := getRestrictionLevel(tt.value); got != tt.want {
+				t.Errorf("getRestrictionLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hasMixedNumbers(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "abc", want: false},
+		{value: "0123456789", want: false},
+		{value: "٠١٢٣", want: false},
+		{value: "12٣", want: true},
+		{value: "𝟎𝟏𝟗", want: false},
+		{value: "𝟗𝟘", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := hasMixedNumbers(tt.value); got != tt.want {
+				t.Errorf("hasMixedNumbers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,537 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// Zero-Width Joiner, which joins emoji in ZWJ sequences
+	emojiZWJ rune = 0x200d
+	// Variation selector 16, which requests the emoji presentation for the preceding character
+	emojiVS16 rune = 0xfe0f
+	// Variation selector 15, which requests the text presentation for the preceding character
+	emojiVS15 rune = 0xfe0e
+	// Combining enclosing keycap, used in keycap sequences such as "1️⃣"
+	emojiKeycap rune = 0x20e3
+	// Waving black flag, the base of tag sequences for subdivision flags
+	emojiBlackFlag rune = 0x1f3f4
+	// Cancel tag, which terminates tag sequences
+	emojiCancelTag rune = 0xe007f
+)
+
+// Characters with the Emoji_Presentation property, which are displayed as emoji by default, from emoji-data.txt
+var emojiPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 1},
+		{Lo: 0x23f0, Hi: 0x23f0, Stride: 1},
+		{Lo: 0x23f3, Hi: 0x23f3, Stride: 1},
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1},
+		{Lo: 0x267f, Hi: 0x267f, Stride: 1},
+		{Lo: 0x2693, Hi: 0x2693, Stride: 1},
+		{Lo: 0x26a1, Hi: 0x26a1, Stride: 1},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 1},
+		{Lo: 0x26ce, Hi: 0x26ce, Stride: 1},
+		{Lo: 0x26d4, Hi: 0x26d4, Stride: 1},
+		{Lo: 0x26ea, Hi: 0x26ea, Stride: 1},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 1},
+		{Lo: 0x26f5, Hi: 0x26f5, Stride: 1},
+		{Lo: 0x26fa, Hi: 0x26fa, Stride: 1},
+		{Lo: 0x26fd, Hi: 0x26fd, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274c, Stride: 1},
+		{Lo: 0x274e, Hi: 0x274e, Stride: 1},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27b0, Hi: 0x27b0, Stride: 1},
+		{Lo: 0x27bf, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b50, Stride: 1},
+		{Lo: 0x2b55, Hi: 0x2b55, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f004, Hi: 0x1f004, Stride: 1},
+		{Lo: 0x1f0cf, Hi: 0x1f0cf, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f1e6, Hi: 0x1f1ff, Stride: 1},
+		{Lo: 0x1f201, Hi: 0x1f201, Stride: 1},
+		{Lo: 0x1f21a, Hi: 0x1f21a, Stride: 1},
+		{Lo: 0x1f22f, Hi: 0x1f22f, Stride: 1},
+		{Lo: 0x1f232, Hi: 0x1f236, Stride: 1},
+		{Lo: 0x1f238, Hi: 0x1f23a, Stride: 1},
+		{Lo: 0x1f250, Hi: 0x1f251, Stride: 1},
+		{Lo: 0x1f300, Hi: 0x1f320, Stride: 1},
+		{Lo: 0x1f32d, Hi: 0x1f335, Stride: 1},
+		{Lo: 0x1f337, Hi: 0x1f37c, Stride: 1},
+		{Lo: 0x1f37e, Hi: 0x1f393, Stride: 1},
+		{Lo: 0x1f3a0, Hi: 0x1f3ca, Stride: 1},
+		{Lo: 0x1f3cf, Hi: 0x1f3d3, Stride: 1},
+		{Lo: 0x1f3e0, Hi: 0x1f3f0, Stride: 1},
+		{Lo: 0x1f3f4, Hi: 0x1f3f4, Stride: 1},
+		{Lo: 0x1f3f8, Hi: 0x1f43e, Stride: 1},
+		{Lo: 0x1f440, Hi: 0x1f440, Stride: 1},
+		{Lo: 0x1f442, Hi: 0x1f4fc, Stride: 1},
+		{Lo: 0x1f4ff, Hi: 0x1f53d, Stride: 1},
+		{Lo: 0x1f54b, Hi: 0x1f54e, Stride: 1},
+		{Lo: 0x1f550, Hi: 0x1f567, Stride: 1},
+		{Lo: 0x1f57a, Hi: 0x1f57a, Stride: 1},
+		{Lo: 0x1f595, Hi: 0x1f596, Stride: 1},
+		{Lo: 0x1f5a4, Hi: 0x1f5a4, Stride: 1},
+		{Lo: 0x1f5fb, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f680, Hi: 0x1f6c5, Stride: 1},
+		{Lo: 0x1f6cc, Hi: 0x1f6cc, Stride: 1},
+		{Lo: 0x1f6d0, Hi: 0x1f6d2, Stride: 1},
+		{Lo: 0x1f6d5, Hi: 0x1f6d7, Stride: 1},
+		{Lo: 0x1f6dc, Hi: 0x1f6df, Stride: 1},
+		{Lo: 0x1f6eb, Hi: 0x1f6ec, Stride: 1},
+		{Lo: 0x1f6f4, Hi: 0x1f6fc, Stride: 1},
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f7f0, Hi: 0x1f7f0, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f93a, Stride: 1},
+		{Lo: 0x1f93c, Hi: 0x1f945, Stride: 1},
+		{Lo: 0x1f947, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x1fa70, Hi: 0x1fa7c, Stride: 1},
+		{Lo: 0x1fa80, Hi: 0x1fa88, Stride: 1},
+		{Lo: 0x1fa90, Hi: 0x1fabd, Stride: 1},
+		{Lo: 0x1fabf, Hi: 0x1fac5, Stride: 1},
+		{Lo: 0x1face, Hi: 0x1fadb, Stride: 1},
+		{Lo: 0x1fae0, Hi: 0x1fae8, Stride: 1},
+		{Lo: 0x1faf0, Hi: 0x1faf8, Stride: 1},
+	},
+}
+
+// Characters with the Emoji_Modifier_Base property, which can be followed by a skin tone modifier, from emoji-data.txt
+var emojiModifierBase = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x261d, Hi: 0x261d, Stride: 1},
+		{Lo: 0x26f9, Hi: 0x26f9, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270d, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f385, Hi: 0x1f385, Stride: 1},
+		{Lo: 0x1f3c2, Hi: 0x1f3c4, Stride: 1},
+		{Lo: 0x1f3c7, Hi: 0x1f3c7, Stride: 1},
+		{Lo: 0x1f3ca, Hi: 0x1f3cc, Stride: 1},
+		{Lo: 0x1f442, Hi: 0x1f443, Stride: 1},
+		{Lo: 0x1f446, Hi: 0x1f450, Stride: 1},
+		{Lo: 0x1f466, Hi: 0x1f478, Stride: 1},
+		{Lo: 0x1f47c, Hi: 0x1f47c, Stride: 1},
+		{Lo: 0x1f481, Hi: 0x1f483, Stride: 1},
+		{Lo: 0x1f485, Hi: 0x1f487, Stride: 1},
+		{Lo: 0x1f48f, Hi: 0x1f48f, Stride: 1},
+		{Lo: 0x1f491, Hi: 0x1f491, Stride: 1},
+		{Lo: 0x1f4aa, Hi: 0x1f4aa, Stride: 1},
+		{Lo: 0x1f574, Hi: 0x1f575, Stride: 1},
+		{Lo: 0x1f57a, Hi: 0x1f57a, Stride: 1},
+		{Lo: 0x1f590, Hi: 0x1f590, Stride: 1},
+		{Lo: 0x1f595, Hi: 0x1f596, Stride: 1},
+		{Lo: 0x1f645, Hi: 0x1f647, Stride: 1},
+		{Lo: 0x1f64b, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f6a3, Hi: 0x1f6a3, Stride: 1},
+		{Lo: 0x1f6b4, Hi: 0x1f6b6, Stride: 1},
+		{Lo: 0x1f6c0, Hi: 0x1f6c0, Stride: 1},
+		{Lo: 0x1f6cc, Hi: 0x1f6cc, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f90c, Stride: 1},
+		{Lo: 0x1f90f, Hi: 0x1f90f, Stride: 1},
+		{Lo: 0x1f918, Hi: 0x1f91f, Stride: 1},
+		{Lo: 0x1f926, Hi: 0x1f926, Stride: 1},
+		{Lo: 0x1f930, Hi: 0x1f939, Stride: 1},
+		{Lo: 0x1f93c, Hi: 0x1f93e, Stride: 1},
+		{Lo: 0x1f977, Hi: 0x1f977, Stride: 1},
+		{Lo: 0x1f9b5, Hi: 0x1f9b6, Stride: 1},
+		{Lo: 0x1f9b8, Hi: 0x1f9b9, Stride: 1},
+		{Lo: 0x1f9bb, Hi: 0x1f9bb, Stride: 1},
+		{Lo: 0x1f9cd, Hi: 0x1f9cf, Stride: 1},
+		{Lo: 0x1f9d1, Hi: 0x1f9dd, Stride: 1},
+		{Lo: 0x1fac3, Hi: 0x1fac5, Stride: 1},
+		{Lo: 0x1faf0, Hi: 0x1faf8, Stride: 1},
+	},
+}
+
+// Subdivision flags that are recommended for general interchange, as the tag characters after the black flag
+var rgiSubdivisionFlags = map[string]struct{}{
+	"gbeng": {},
+	"gbsct": {},
+	"gbwls": {},
+}
+
+// ZWJ sequences that are recommended for general interchange, from emoji-zwj-sequences.txt (Emoji 15.1)
+// Most sequences are combinations of people with genders, roles, and skin tones, so the list is built from those
+var rgiZWJSequences = func() map[string]struct{} {
+	zwj := string(emojiZWJ)
+	female := zwj + "\u2640\uFE0F"
+	male := zwj + "\u2642\uFE0F"
+	facingRight := zwj + "\u27A1\uFE0F"
+	person, man, woman := "\U0001F9D1", "\U0001F468", "\U0001F469"
+	// Skin tone modifiers, including no skin tone
+	tones := []string{"", "\U0001F3FB", "\U0001F3FC", "\U0001F3FD", "\U0001F3FE", "\U0001F3FF"}
+
+	res := map[string]struct{}{}
+	add := func(seqs ...string) {
+		for _, seq := range seqs {
+			res[seq] = struct{}{}
+		}
+	}
+
+	// Families
+	boy, girl, child := "\U0001F466", "\U0001F467", "\U0001F9D2"
+	for _, parents := range []string{man, woman, man + zwj + man, man + zwj + woman, woman + zwj + woman} {
+		for _, children := range []string{boy, boy + zwj + boy, girl, girl + zwj + boy, girl + zwj + girl} {
+			add(parents + zwj + children)
+		}
+	}
+	add(
+		person+zwj+child,
+		person+zwj+child+zwj+child,
+		person+zwj+person+zwj+child,
+		person+zwj+person+zwj+child+zwj+child,
+	)
+
+	// People with roles and hair styles, such as "woman astronaut"
+	whiteCane, motorizedWheelchair, manualWheelchair := "\U0001F9AF", "\U0001F9BC", "\U0001F9BD"
+	roles := []string{
+		"\u2695\uFE0F", "\u2696\uFE0F", "\u2708\uFE0F", "\U0001F33E", "\U0001F373", "\U0001F37C", "\U0001F393", "\U0001F3A4",
+		"\U0001F3A8", "\U0001F3EB", "\U0001F3ED", "\U0001F4BB", "\U0001F4BC", "\U0001F527", "\U0001F52C", "\U0001F680",
+		"\U0001F692", "\U0001F9B0", "\U0001F9B1", "\U0001F9B2", "\U0001F9B3",
+		whiteCane, motorizedWheelchair, manualWheelchair,
+	}
+	for _, tone := range tones {
+		for _, p := range []string{person, man, woman} {
+			for _, role := range roles {
+				add(p + tone + zwj + role)
+			}
+			add(
+				p+tone+zwj+whiteCane+facingRight,
+				p+tone+zwj+motorizedWheelchair+facingRight,
+				p+tone+zwj+manualWheelchair+facingRight,
+			)
+		}
+		// Mx Claus
+		add(person + tone + zwj + "\U0001F384")
+	}
+
+	// People with a gender, such as "woman running"
+	gendered := []string{
+		"\U0001F3C3", "\U0001F3C4", "\U0001F3CA", "\U0001F46E", "\U0001F470", "\U0001F471", "\U0001F473", "\U0001F477",
+		"\U0001F481", "\U0001F482", "\U0001F486", "\U0001F487", "\U0001F645", "\U0001F646", "\U0001F647", "\U0001F64B",
+		"\U0001F64D", "\U0001F64E", "\U0001F6A3", "\U0001F6B4", "\U0001F6B5", "\U0001F6B6", "\U0001F926", "\U0001F935",
+		"\U0001F937", "\U0001F938", "\U0001F939", "\U0001F93D", "\U0001F93E", "\U0001F9B8", "\U0001F9B9", "\U0001F9CD",
+		"\U0001F9CE", "\U0001F9CF", "\U0001F9D4", "\U0001F9D6", "\U0001F9D7", "\U0001F9D8", "\U0001F9D9", "\U0001F9DA",
+		"\U0001F9DB", "\U0001F9DC", "\U0001F9DD",
+	}
+	// Bases that are displayed as text by default, which need the emoji variation selector when they don't have a skin tone
+	genderedText := []string{"\U0001F3CB", "\U0001F3CC", "\U0001F575", "\u26F9"}
+	for _, tone := range tones {
+		for _, base := range gendered {
+			add(base+tone+female, base+tone+male)
+		}
+		for _, base := range genderedText {
+			b := base + tone
+			if tone == "" {
+				b = base + string(emojiVS16)
+			}
+			add(b+female, b+male)
+		}
+		// Walking, kneeling, and running facing right
+		for _, base := range []string{"\U0001F6B6", "\U0001F9CE", "\U0001F3C3"} {
+			add(base+tone+facingRight, base+tone+female+facingRight, base+tone+male+facingRight)
+		}
+	}
+	// Bases that don't support skin tones
+	for _, base := range []string{"\U0001F46F", "\U0001F93C", "\U0001F9DE", "\U0001F9DF"} {
+		add(base+female, base+male)
+	}
+
+	// Couples and people holding hands
+	// Without skin tones, most of these are single characters, so they are ZWJ sequences only for some combinations of people
+	heart := zwj + "\u2764\uFE0F" + zwj
+	kiss := heart + "\U0001F48B" + zwj
+	holdingHands := zwj + "\U0001F91D" + zwj
+	add(person + holdingHands + person)
+	for _, t1 := range tones {
+		for _, t2 := range tones {
+			if (t1 == "") != (t2 == "") {
+				continue
+			}
+			for _, pair := range [][2]string{{woman, man}, {man, man}, {woman, woman}} {
+				add(pair[0]+t1+heart+pair[1]+t2, pair[0]+t1+kiss+pair[1]+t2)
+				if t1 != t2 {
+					add(pair[0] + t1 + holdingHands + pair[1] + t2)
+				}
+			}
+			if t1 != "" {
+				add(person + t1 + holdingHands + person + t2)
+			}
+			if t1 != t2 {
+				add(
+					person+t1+heart+person+t2,
+					person+t1+kiss+person+t2,
+					// Handshake
+					"\U0001FAF1"+t1+zwj+"\U0001FAF2"+t2,
+				)
+			}
+		}
+	}
+
+	// Other sequences
+	add(
+		"\U0001F3F3\uFE0F\u200D\U0001F308",       // Rainbow flag
+		"\U0001F3F3\uFE0F\u200D\u26A7\uFE0F",     // Transgender flag
+		"\U0001F3F4\u200D\u2620\uFE0F",           // Pirate flag
+		"\U0001F415\u200D\U0001F9BA",             // Service dog
+		"\U0001F408\u200D\u2B1B",                 // Black cat
+		"\U0001F43B\u200D\u2744\uFE0F",           // Polar bear
+		"\U0001F426\u200D\u2B1B",                 // Black bird
+		"\U0001F426\u200D\U0001F525",             // Phoenix
+		"\U0001F34B\u200D\U0001F7E9",             // Lime
+		"\U0001F344\u200D\U0001F7EB",             // Brown mushroom
+		"\u2764\uFE0F\u200D\U0001F525",           // Heart on fire
+		"\u2764\uFE0F\u200D\U0001FA79",           // Mending heart
+		"\u26D3\uFE0F\u200D\U0001F4A5",           // Broken chain
+		"\U0001F441\uFE0F\u200D\U0001F5E8\uFE0F", // Eye in speech bubble
+		"\U0001F62E\u200D\U0001F4A8",             // Face exhaling
+		"\U0001F635\u200D\U0001F4AB",             // Face with spiral eyes
+		"\U0001F636\u200D\U0001F32B\uFE0F",       // Face in clouds
+		"\U0001F642\u200D\u2194\uFE0F",           // Head shaking horizontally
+		"\U0001F642\u200D\u2195\uFE0F",           // Head shaking vertically
+	)
+
+	return res
+}()
+
+// rgiSubdivisionTagsLength returns the length in bytes of the tags at the beginning of s, if they form a subdivision flag that is recommended for general interchange when they follow a black flag, or 0 otherwise
+// The tags include the cancel tag that terminates the sequence
+func rgiSubdivisionTagsLength(s string) int {
+	tags := make([]rune, 0, 8)
+	for i, r := range s {
+		switch {
+		case r == emojiCancelTag:
+			if _, ok := rgiSubdivisionFlags[string(tags)]; ok {
+				return i + utf8.RuneLen(r)
+			}
+			return 0
+		case isEmojiTag(r) && len(tags) < 8:
+			tags = append(tags, r-0xe0000)
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// emojiKind is the result of classifying a grapheme cluster with classifyEmoji
+type emojiKind int
+
+const (
+	// The cluster is not an emoji
+	emojiNone emojiKind = iota
+	// The cluster is a well-formed emoji
+	emojiValid
+	// The cluster contains emoji characters, but it's not a well-formed emoji (for example, a skin tone modifier on its own or an invalid flag)
+	emojiInvalid
+)
+
+// isEmojiModifier returns true if r is a skin tone modifier
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+// isRegionalIndicator returns true if r is a regional indicator symbol, which are used in pairs for country flags
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isEmojiTag returns true if r is a tag character, which are used in tag sequences for subdivision flags
+func isEmojiTag(r rune) bool {
+	return r >= 0xe0020 && r <= 0xe007f
+}
+
+// isKeycapBase returns true if r can be the base of a keycap sequence
+func isKeycapBase(r rune) bool {
+	return (r >= '0' && r <= '9') || r == '#' || r == '*'
+}
+
+// isEmojiComponent returns true if r is a character that is used in emoji sequences but is not an emoji on its own
+func isEmojiComponent(r rune) bool {
+	return r == emojiZWJ || r == emojiVS16 || r == emojiKeycap ||
+		isEmojiModifier(r) || isRegionalIndicator(r) || isEmojiTag(r)
+}
+
+// classifyEmoji returns whether the grapheme cluster is an emoji, and if it's well-formed as defined by UTS #51
+// Characters that are displayed as text by default, such as "©", are emoji only if they're followed by the emoji variation selector (U+FE0F)
+// Flags are checked against the list of regions, and ZWJ sequences against the list of sequences that are recommended for general interchange
+func classifyEmoji(cluster string) emojiKind {
+	runes := []rune(cluster)
+	first := runes[0]
+
+	switch {
+	case isRegionalIndicator(first):
+		// Flags are made of two regional indicators for the region code
+		if len(runes) != 2 || !isRegionalIndicator(runes[1]) {
+			return emojiInvalid
+		}
+		code := string([]rune{first - 0x1f1e6 + 'A', runes[1] - 0x1f1e6 + 'A'})
+		if code == "EU" || code == "UN" {
+			return emojiValid
+		}
+		region, err := language.ParseRegion(code)
+		if err != nil || !region.IsCountry() || region.String() != code {
+			return emojiInvalid
+		}
+		return emojiValid
+
+	case isKeycapBase(first):
+		if !strings.ContainsRune(cluster, emojiKeycap) {
+			return classifyNonEmojiCluster(runes)
+		}
+		// Keycap sequences are made of the base, the emoji variation selector, and the keycap
+		if len(runes) != 3 || runes[1] != emojiVS16 || runes[2] != emojiKeycap {
+			return emojiInvalid
+		}
+		return emojiValid
+
+	case first == emojiBlackFlag && len(runes) > 1 && isEmojiTag(runes[1]):
+		// Tag sequences are made of the black flag, the tags for the subdivision, and the cancel tag
+		if runes[len(runes)-1] != emojiCancelTag {
+			return emojiInvalid
+		}
+		tags := make([]rune, 0, len(runes)-2)
+		for _, r := range runes[1 : len(runes)-1] {
+			if !isEmojiTag(r) || r == emojiCancelTag {
+				return emojiInvalid
+			}
+			tags = append(tags, r-0xe0000)
+		}
+		if _, ok := rgiSubdivisionFlags[string(tags)]; !ok {
+			return emojiInvalid
+		}
+		return emojiValid
+
+	case isExtendedPictographic(first):
+		// Characters that are displayed as text by default are not emoji, unless they're followed by the emoji variation selector or they're part of a sequence
+		if !unicode.Is(emojiPresentation, first) && (len(runes) == 1 || runes[1] == emojiVS15) {
+			return classifyNonEmojiCluster(runes)
+		}
+		return classifyZWJSequence(runes)
+
+	default:
+		return classifyNonEmojiCluster(runes)
+	}
+}
+
+// classifyNonEmojiCluster classifies a cluster that doesn't start with an emoji, which is invalid if it contains emoji components such as skin tone modifiers
+// The emoji variation selector and ZWJ are allowed, as they are ignored after characters that are not emoji
+func classifyNonEmojiCluster(runes []rune) emojiKind {
+	for _, r := range runes {
+		if r != emojiZWJ && r != emojiVS16 && isEmojiComponent(r) {
+			return emojiInvalid
+		}
+	}
+	return emojiNone
+}
+
+// classifyZWJSequence classifies a cluster that starts with an emoji, which can be a single emoji or a ZWJ sequence
+// Each element in the sequence must be an emoji, optionally followed by either a skin tone modifier (if it's a modifier base) or the emoji variation selector (required for characters that are displayed as text by default)
+// ZWJ sequences must also be in the list of sequences that are recommended for general interchange
+func classifyZWJSequence(runes []rune) emojiKind {
+	joined := false
+	for i := 0; i < len(runes); i++ {
+		// Base of the element
+		r := runes[i]
+		if !isExtendedPictographic(r) {
+			return emojiInvalid
+		}
+
+		// Optional modifier or variation selector
+		hasPresentation := unicode.Is(emojiPresentation, r)
+		if i+1 < len(runes) {
+			switch next := runes[i+1]; {
+			case isEmojiModifier(next):
+				if !unicode.Is(emojiModifierBase, r) {
+					return emojiInvalid
+				}
+				hasPresentation = true
+				i++
+			case next == emojiVS16:
+				hasPresentation = true
+				i++
+			}
+		}
+		if !hasPresentation {
+			return emojiInvalid
+		}
+
+		// The element must be followed by a ZWJ and another element, or be at the end
+		if i+1 == len(runes) {
+			if joined {
+				if _, ok := rgiZWJSequences[string(runes)]; !ok {
+					return emojiInvalid
+				}
+			}
+			return emojiValid
+		}
+		if runes[i+1] != emojiZWJ || i+2 == len(runes) {
+			return emojiInvalid
+		}
+		joined = true
+		i++
+	}
+	return emojiInvalid
+}
+
+// stripEmoji removes all emoji from s, including invalid sequences and stray emoji components such as skin tone modifiers
+func stripEmoji(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		cluster := s[:n]
+		s = s[n:]
+
+		switch classifyEmoji(cluster) {
+		case emojiNone:
+			sb.WriteString(cluster)
+		case emojiInvalid:
+			// Keep the characters that are not emoji, such as a letter or digit followed by a skin tone modifier
+			first, _ := utf8.DecodeRuneInString(cluster)
+			if isExtendedPictographic(first) || isRegionalIndicator(first) {
+				continue
+			}
+			for _, r := range cluster {
+				if !isEmojiComponent(r) {
+					sb.WriteRune(r)
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// findEmoji iterates through the emoji in s, calling fn with each emoji sequence, its kind, and its position (1-based, counted in characters)
+// If fn returns false, the iteration stops
+func findEmoji(s string, fn func(cluster string, kind emojiKind, pos int) bool) {
+	pos := 1
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		cluster := s[:n]
+		s = s[n:]
+
+		kind := classifyEmoji(cluster)
+		if kind != emojiNone && !fn(cluster, kind, pos) {
+			return
+		}
+		pos += utf8.RuneCountInString(cluster)
+	}
+}
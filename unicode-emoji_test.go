@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"testing"
+)
+
+func Test_classifyEmoji(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster string
+		want    emojiKind
+	}{
+		{name: "letter", cluster: "a", want: emojiNone},
+		{name: "digit", cluster: "1", want: emojiNone},
+		{name: "emoji", cluster: "\U0001F600", want: emojiValid},
+		{name: "text presentation", cluster: "©", want: emojiNone},
+		{name: "text with VS15", cluster: "❤︎", want: emojiNone},
+		{name: "text with VS16", cluster: "❤️", want: emojiValid},
+		{name: "skin tone", cluster: "\U0001F44D\U0001F3FD", want: emojiValid},
+		{name: "skin tone on non-base", cluster: "\U0001F600\U0001F3FD", want: emojiInvalid},
+		{name: "lone skin tone", cluster: "\U0001F3FD", want: emojiInvalid},
+		{name: "letter with skin tone", cluster: "a\U0001F3FD", want: emojiInvalid},
+		{name: "flag", cluster: "\U0001F1EE\U0001F1F9", want: emojiValid},
+		{name: "EU flag", cluster: "\U0001F1EA\U0001F1FA", want: emojiValid},
+		{name: "invalid flag", cluster: "\U0001F1FD\U0001F1FD", want: emojiInvalid},
+		{name: "lone regional indicator", cluster: "\U0001F1EE", want: emojiInvalid},
+		{name: "keycap", cluster: "1️⃣", want: emojiValid},
+		{name: "keycap without VS16", cluster: "#⃣", want: emojiInvalid},
+		{name: "subdivision flag", cluster: "\U0001F3F4\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", want: emojiValid},
+		{name: "invalid subdivision flag", cluster: "\U0001F3F4\U000E0078\U000E0078\U000E007F", want: emojiInvalid},
+		{name: "unterminated subdivision flag", cluster: "\U0001F3F4\U000E0067\U000E0062", want: emojiInvalid},
+		{name: "ZWJ sequence", cluster: "\U0001F469‍\U0001F4BB", want: emojiValid},
+		{name: "family", cluster: "\U0001F468‍\U0001F469‍\U0001F467‍\U0001F466", want: emojiValid},
+		{name: "ZWJ sequence with skin tone", cluster: "\U0001F9D1\U0001F3FD‍\U0001F680", want: emojiValid},
+		{name: "rainbow flag", cluster: "\U0001F3F3️‍\U0001F308", want: emojiValid},
+		{name: "ZWJ with text presentation", cluster: "\U0001F3F3‍\U0001F308", want: emojiInvalid},
+		{name: "trailing ZWJ", cluster: "\U0001F600‍", want: emojiInvalid},
+		{name: "ZWJ sequence not recommended", cluster: "\U0001F436‍\U0001F355", want: emojiInvalid},
+		{name: "gendered with text presentation", cluster: "\U0001F3CB️‍♀️", want: emojiValid},
+		{name: "gendered with text presentation and skin tone", cluster: "\U0001F3CB\U0001F3FC‍♀️", want: emojiValid},
+		{name: "facing right", cluster: "\U0001F469\U0001F3FE‍\U0001F9BD‍➡️", want: emojiValid},
+		{name: "couple with skin tones", cluster: "\U0001F9D1\U0001F3FB‍❤️‍\U0001F48B‍\U0001F9D1\U0001F3FF", want: emojiValid},
+		{name: "couple with the same skin tone", cluster: "\U0001F9D1\U0001F3FB‍❤️‍\U0001F9D1\U0001F3FB", want: emojiInvalid},
+		{name: "gender on base without gender", cluster: "\U0001F600‍♀️", want: emojiInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyEmoji(tt.cluster); got != tt.want {
+				t.Errorf("classifyEmoji(%+q) = %v, want %v", tt.cluster, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_rgiZWJSequences(t *testing.T) {
+	// All sequences in the list must be well-formed, and a single grapheme cluster
+	for seq := range rgiZWJSequences {
+		if nextGrapheme(seq) != len(seq) {
+			t.Errorf("sequence %+q is not a single grapheme cluster", seq)
+		}
+		if got := classifyEmoji(seq); got != emojiValid {
+			t.Errorf("classifyEmoji(%+q) = %v, want %v", seq, got, emojiValid)
+		}
+	}
+}
+
+func Test_stripEmoji(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "hello", want: "hello"},
+		{value: "hi \U0001F600 there", want: "hi  there"},
+		{value: "\U0001F468‍\U0001F469‍\U0001F467 family", want: " family"},
+		{value: "Italy \U0001F1EE\U0001F1F9", want: "Italy "},
+		{value: "a\U0001F3FDb", want: "ab"},
+		{value: "© 2024", want: "© 2024"},
+		{value: "1️⃣ first", want: " first"},
+		{value: "1🏽 first", want: "1 first"},
+		{value: "#⃣ hash", want: "# hash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := stripEmoji(tt.value); got != tt.want {
+				t.Errorf("stripEmoji() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package validator
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeBreakProperty is the value of the Grapheme_Cluster_Break property of a character, as defined by UAX #29
+type graphemeBreakProperty uint8
+
+const (
+	gbOther graphemeBreakProperty = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+// Characters with the Grapheme_Cluster_Break property "Prepend" that are not Prepended_Concatenation_Mark
+var graphemePrepend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0d4e, Hi: 0x0d4e, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x111c2, Hi: 0x111c3, Stride: 1},
+		{Lo: 0x1193f, Hi: 0x1193f, Stride: 1},
+		{Lo: 0x11941, Hi: 0x11941, Stride: 1},
+		{Lo: 0x11a3a, Hi: 0x11a3a, Stride: 1},
+		{Lo: 0x11a84, Hi: 0x11a89, Stride: 1},
+		{Lo: 0x11d46, Hi: 0x11d46, Stride: 1},
+		{Lo: 0x11f02, Hi: 0x11f02, Stride: 1},
+	},
+}
+
+// Characters in the category Mc that don't have the Grapheme_Cluster_Break property "SpacingMark"
+var graphemeNotSpacingMark = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x102b, Hi: 0x102c, Stride: 1},
+		{Lo: 0x1038, Hi: 0x1038, Stride: 1},
+		{Lo: 0x1062, Hi: 0x1064, Stride: 1},
+		{Lo: 0x1067, Hi: 0x106d, Stride: 1},
+		{Lo: 0x1083, Hi: 0x1083, Stride: 1},
+		{Lo: 0x1087, Hi: 0x108c, Stride: 1},
+		{Lo: 0x108f, Hi: 0x108f, Stride: 1},
+		{Lo: 0x109a, Hi: 0x109c, Stride: 1},
+		{Lo: 0x1a61, Hi: 0x1a61, Stride: 1},
+		{Lo: 0x1a63, Hi: 0x1a64, Stride: 1},
+		{Lo: 0xaa7b, Hi: 0xaa7b, Stride: 1},
+		{Lo: 0xaa7d, Hi: 0xaa7d, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x11720, Hi: 0x11721, Stride: 1},
+	},
+}
+
+// Characters with the Extended_Pictographic property, from emoji-data.txt
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00a9, Stride: 1},
+		{Lo: 0x00ae, Hi: 0x00ae, Stride: 1},
+		{Lo: 0x203c, Hi: 0x203c, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x2388, Hi: 0x2388, Stride: 1},
+		{Lo: 0x23cf, Hi: 0x23cf, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23f3, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25b6, Stride: 1},
+		{Lo: 0x25c0, Hi: 0x25c0, Stride: 1},
+		{Lo: 0x25fb, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2600, Hi: 0x2605, Stride: 1},
+		{Lo: 0x2607, Hi: 0x2612, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2685, Stride: 1},
+		{Lo: 0x2690, Hi: 0x2705, Stride: 1},
+		{Lo: 0x2708, Hi: 0x2712, Stride: 1},
+		{Lo: 0x2714, Hi: 0x2714, Stride: 1},
+		{Lo: 0x2716, Hi: 0x2716, Stride: 1},
+		{Lo: 0x271d, Hi: 0x271d, Stride: 1},
+		{Lo: 0x2721, Hi: 0x2721, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x2733, Hi: 0x2734, Stride: 1},
+		{Lo: 0x2744, Hi: 0x2744, Stride: 1},
+		{Lo: 0x2747, Hi: 0x2747, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274c, Stride: 1},
+		{Lo: 0x274e, Hi: 0x274e, Stride: 1},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2763, Hi: 0x2767, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27a1, Hi: 0x27a1, Stride: 1},
+		{Lo: 0x27b0, Hi: 0x27b0, Stride: 1},
+		{Lo: 0x27bf, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b50, Stride: 1},
+		{Lo: 0x2b55, Hi: 0x2b55, Stride: 1},
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1},
+		{Lo: 0x303d, Hi: 0x303d, Stride: 1},
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1},
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f000, Hi: 0x1f0ff, Stride: 1},
+		{Lo: 0x1f10d, Hi: 0x1f10f, Stride: 1},
+		{Lo: 0x1f12f, Hi: 0x1f12f, Stride: 1},
+		{Lo: 0x1f16c, Hi: 0x1f171, Stride: 1},
+		{Lo: 0x1f17e, Hi: 0x1f17f, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f1ad, Hi: 0x1f1e5, Stride: 1},
+		{Lo: 0x1f201, Hi: 0x1f20f, Stride: 1},
+		{Lo: 0x1f21a, Hi: 0x1f21a, Stride: 1},
+		{Lo: 0x1f22f, Hi: 0x1f22f, Stride: 1},
+		{Lo: 0x1f232, Hi: 0x1f23a, Stride: 1},
+		{Lo: 0x1f23c, Hi: 0x1f23f, Stride: 1},
+		{Lo: 0x1f249, Hi: 0x1f3fa, Stride: 1},
+		{Lo: 0x1f400, Hi: 0x1f53d, Stride: 1},
+		{Lo: 0x1f546, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1},
+		{Lo: 0x1f774, Hi: 0x1f77f, Stride: 1},
+		{Lo: 0x1f7d5, Hi: 0x1f7ff, Stride: 1},
+		{Lo: 0x1f80c, Hi: 0x1f80f, Stride: 1},
+		{Lo: 0x1f848, Hi: 0x1f84f, Stride: 1},
+		{Lo: 0x1f85a, Hi: 0x1f85f, Stride: 1},
+		{Lo: 0x1f888, Hi: 0x1f88f, Stride: 1},
+		{Lo: 0x1f8ae, Hi: 0x1f8ff, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f93a, Stride: 1},
+		{Lo: 0x1f93c, Hi: 0x1f945, Stride: 1},
+		{Lo: 0x1f947, Hi: 0x1faff, Stride: 1},
+		{Lo: 0x1fc00, Hi: 0x1fffd, Stride: 1},
+	},
+}
+
+// getGraphemeBreakProperty returns the value of the Grapheme_Cluster_Break property for r
+// The property is derived from the Unicode tables in the standard library where possible
+func getGraphemeBreakProperty(r rune) graphemeBreakProperty {
+	switch {
+	case r < 0x7f:
+		// Fast path for ASCII
+		switch {
+		case r == '\r':
+			return gbCR
+		case r == '\n':
+			return gbLF
+		case r < 0x20:
+			return gbControl
+		default:
+			return gbOther
+		}
+	case r == 0x200d:
+		return gbZWJ
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return gbRegionalIndicator
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// Emoji modifiers (skin tones)
+		return gbExtend
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend):
+		return gbExtend
+	case unicode.In(r, unicode.Prepended_Concatenation_Mark, graphemePrepend):
+		return gbPrepend
+	case r == 0x2028 || r == 0x2029 || unicode.In(r, unicode.Cc, unicode.Cf, unicode.Cs):
+		return gbControl
+	case r == 0x0e33 || r == 0x0eb3 || (unicode.Is(unicode.Mc, r) && !unicode.Is(graphemeNotSpacingMark, r)):
+		return gbSpacingMark
+	case (r >= 0x1100 && r <= 0x115f) || (r >= 0xa960 && r <= 0xa97c):
+		return gbL
+	case (r >= 0x1160 && r <= 0x11a7) || (r >= 0xd7b0 && r <= 0xd7c6):
+		return gbV
+	case (r >= 0x11a8 && r <= 0x11ff) || (r >= 0xd7cb && r <= 0xd7fb):
+		return gbT
+	case r >= 0xac00 && r <= 0xd7a3:
+		// Precomposed Hangul syllables are LV if they have no trailing consonant
+		if (r-0xac00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	default:
+		return gbOther
+	}
+}
+
+// isExtendedPictographic returns true if r has the Extended_Pictographic property
+func isExtendedPictographic(r rune) bool {
+	return r >= 0xa9 && unicode.Is(extendedPictographic, r)
+}
+
+// nextGrapheme returns the length in bytes of the first extended grapheme cluster in s, as defined by UAX #29
+func nextGrapheme(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 {
+		return 0
+	}
+
+	prev := getGraphemeBreakProperty(r)
+	// True if the cluster so far ends with an Extended_Pictographic character followed by Extend characters
+	inPictographic := isExtendedPictographic(r)
+	// True if the previous character is a ZWJ that follows a pictographic sequence
+	afterPictographicZWJ := false
+	// Number of consecutive regional indicators at the end of the cluster
+	regionalIndicators := 0
+	if prev == gbRegionalIndicator {
+		regionalIndicators = 1
+	}
+
+	pos := n
+	for pos < len(s) {
+		r, n = utf8.DecodeRuneInString(s[pos:])
+		cur := getGraphemeBreakProperty(r)
+		pictographic := isExtendedPictographic(r)
+		if graphemeBreak(prev, cur, pictographic, afterPictographicZWJ, regionalIndicators) {
+			break
+		}
+
+		afterPictographicZWJ = cur == gbZWJ && inPictographic
+		inPictographic = pictographic || (inPictographic && cur == gbExtend)
+		if cur == gbRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev = cur
+		pos += n
+	}
+
+	return pos
+}
+
+// graphemeBreak returns true if there's a grapheme cluster boundary between two characters, following the rules in UAX #29
+func graphemeBreak(prev, cur graphemeBreakProperty, curPictographic bool, afterPictographicZWJ bool, regionalIndicators int) bool {
+	switch {
+	// GB3
+	case prev == gbCR && cur == gbLF:
+		return false
+	// GB4 and GB5
+	case prev == gbControl || prev == gbCR || prev == gbLF,
+		cur == gbControl || cur == gbCR || cur == gbLF:
+		return true
+	// GB6
+	case prev == gbL && (cur == gbL || cur == gbV || cur == gbLV || cur == gbLVT):
+		return false
+	// GB7
+	case (prev == gbLV || prev == gbV) && (cur == gbV || cur == gbT):
+		return false
+	// GB8
+	case (prev == gbLVT || prev == gbT) && cur == gbT:
+		return false
+	// GB9, GB9a, and GB9b
+	case cur == gbExtend || cur == gbZWJ || cur == gbSpacingMark || prev == gbPrepend:
+		return false
+	// GB11
+	case prev == gbZWJ && afterPictographicZWJ && curPictographic:
+		return false
+	// GB12 and GB13
+	case prev == gbRegionalIndicator && cur == gbRegionalIndicator:
+		return regionalIndicators%2 == 0
+	// GB999
+	default:
+		return true
+	}
+}
+
+// graphemeCount returns the number of extended grapheme clusters in s
+func graphemeCount(s string) int {
+	count := 0
+	for len(s) > 0 {
+		s = s[nextGrapheme(s):]
+		count++
+	}
+	return count
+}
@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_nextGrapheme(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "empty", value: "", want: nil},
+		{name: "ASCII", value: "abc", want: []string{"a", "b", "c"}},
+		{name: "CRLF", value: "a\r\nb\n\r", want: []string{"a", "\r\n", "b", "\n", "\r"}},
+		{name: "combining marks", value: "éạ̈", want: []string{"é", "ạ̈"}},
+		{name: "CJK", value: "日本語", want: []string{"日", "本", "語"}},
+		{name: "Hangul syllables", value: "한국어", want: []string{"한", "국", "어"}},
+		{name: "Hangul jamo", value: "각ᄀ", want: []string{"각", "ᄀ"}},
+		{name: "Devanagari", value: "नमस्ते", want: []string{"न", "म", "स्", "ते"}},
+		{name: "emoji with skin tone", value: "👍🏽👍", want: []string{"👍🏽", "👍"}},
+		{name: "ZWJ sequence", value: "👩‍👩‍👧‍👦x", want: []string{"👩‍👩‍👧‍👦", "x"}},
+		{name: "ZWJ sequence with skin tones", value: "🧑🏻‍🤝‍🧑🏿", want: []string{"🧑🏻‍🤝‍🧑🏿"}},
+		{name: "ZWJ not after pictographic", value: "a‍👍", want: []string{"a‍", "👍"}},
+		{name: "flags", value: "🇮🇹🇺🇸🇩", want: []string{"🇮🇹", "🇺🇸", "🇩"}},
+		{name: "keycap", value: "1️⃣2", want: []string{"1️⃣", "2"}},
+		{name: "tag sequence", value: "🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f!", want: []string{"🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f", "!"}},
+		{name: "prepend", value: "؀١a", want: []string{"؀١", "a"}},
+		{name: "control characters", value: "a\u0007́", want: []string{"a", "\u0007", "́"}},
+		{name: "invalid UTF-8", value: "a\xffb", want: []string{"a", "\xff", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			s := tt.value
+			for len(s) > 0 {
+				n := nextGrapheme(s)
+				got = append(got, s[:n])
+				s = s[n:]
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextGrapheme() split = %q, want %q", got, tt.want)
+			}
+			if n := graphemeCount(tt.value); n != len(tt.want) {
+				t.Errorf("graphemeCount() = %d, want %d", n, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transliterations of non-ASCII characters that can't be converted to ASCII by decomposing them and removing combining marks
+// Letters are listed in lowercase only; uppercase letters use the uppercase (or titlecase) version of the transliteration
+var translitTable = map[rune]string{
+	// Latin
+	'ß': "ss",
+	'æ': "ae",
+	'œ': "oe",
+	'ø': "o",
+	'đ': "d",
+	'ð': "d",
+	'þ': "th",
+	'ł': "l",
+	'ħ': "h",
+	'ı': "i",
+	'ĸ': "k",
+	'ŋ': "ng",
+	'ŧ': "t",
+	'ƒ': "f",
+	'ɐ': "a",
+	'ə': "e",
+	'ɛ': "e",
+	'ɨ': "i",
+	'ʉ': "u",
+
+	// Greek, based on ISO 843
+	'α': "a",
+	'β': "v",
+	'γ': "g",
+	'δ': "d",
+	'ε': "e",
+	'ζ': "z",
+	'η': "i",
+	'θ': "th",
+	'ι': "i",
+	'κ': "k",
+	'λ': "l",
+	'μ': "m",
+	'ν': "n",
+	'ξ': "x",
+	'ο': "o",
+	'π': "p",
+	'ρ': "r",
+	'σ': "s",
+	'ς': "s",
+	'τ': "t",
+	'υ': "y",
+	'φ': "f",
+	'χ': "ch",
+	'ψ': "ps",
+	'ω': "o",
+
+	// Cyrillic, based on the ICAO Doc 9303 romanization (with additional letters for Ukrainian, Belarusian, Serbian, and Macedonian)
+	'а': "a",
+	'б': "b",
+	'в': "v",
+	'г': "g",
+	'ґ': "g",
+	'д': "d",
+	'ђ': "dj",
+	'е': "e",
+	'є': "ie",
+	'ж': "zh",
+	'з': "z",
+	'ѕ': "dz",
+	'и': "i",
+	'і': "i",
+	'ј': "j",
+	'к': "k",
+	'л': "l",
+	'љ': "lj",
+	'м': "m",
+	'н': "n",
+	'њ': "nj",
+	'о': "o",
+	'п': "p",
+	'р': "r",
+	'с': "s",
+	'т': "t",
+	'ћ': "c",
+	'у': "u",
+	'ф': "f",
+	'х': "kh",
+	'ц': "ts",
+	'ч': "ch",
+	'џ': "dz",
+	'ш': "sh",
+	'щ': "shch",
+	'ъ': "ie",
+	'ы': "y",
+	'ь': "",
+	'э': "e",
+	'ю': "iu",
+	'я': "ia",
+
+	// Punctuation and symbols
+	'‘': "'",
+	'’': "'",
+	'‚': "'",
+	'‛': "'",
+	'′': "'",
+	'‹': "'",
+	'›': "'",
+	'“': `"`,
+	'”': `"`,
+	'„': `"`,
+	'‟': `"`,
+	'″': `"`,
+	'«': `"`,
+	'»': `"`,
+	'‐': "-",
+	'‑': "-",
+	'‒': "-",
+	'–': "-",
+	'—': "-",
+	'―': "-",
+	'−': "-",
+	'⁄': "/",
+	'•': "*",
+	'·': ".",
+	'×': "x",
+	'÷': "/",
+	'¡': "!",
+	'¿': "?",
+	'©': "(c)",
+	'®': "(r)",
+	'°': "deg",
+	'€': "EUR",
+	'£': "GBP",
+	'¥': "JPY",
+	'¢': "c",
+}
+
+// transliterateASCII converts s to ASCII
+// Characters are decomposed (using the NFKD form) and combining marks are removed, then the characters in translitTable are replaced; all other non-ASCII characters are removed, except for whitespace characters, which are replaced with spaces
+func transliterateASCII(s string) string {
+	// Fast path for strings that are already ASCII
+	if isASCII(s) {
+		return s
+	}
+
+	s = norm.NFKD.String(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			sb.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Remove combining marks
+		case unicode.IsSpace(r):
+			sb.WriteByte(' ')
+		default:
+			sb.WriteString(transliterateRune(r, s[i+utf8.RuneLen(r):]))
+		}
+	}
+	return sb.String()
+}
+
+// transliterateRune returns the transliteration of r from translitTable, or an empty string if it's not in the table
+// For uppercase letters, the transliteration is in titlecase if the next letter in rest is lowercase (e.g. "Ж" is "Zh" in "Жуков" and "ZH" in "ЖУКОВ")
+func transliterateRune(r rune, rest string) string {
+	if t, ok := translitTable[r]; ok {
+		return t
+	}
+
+	lower := unicode.ToLower(r)
+	t, ok := translitTable[lower]
+	if !ok || lower == r || t == "" {
+		return ""
+	}
+	if len(t) == 1 {
+		return strings.ToUpper(t)
+	}
+
+	// Find the next letter, skipping combining marks
+	for _, next := range rest {
+		if unicode.Is(unicode.Mn, next) {
+			continue
+		}
+		if unicode.IsLower(next) {
+			return strings.ToUpper(t[:1]) + t[1:]
+		}
+		break
+	}
+	return strings.ToUpper(t)
+}
+
+// isASCII returns true if s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// firstNonASCII returns the first non-ASCII character in s and its position (1-based, counted in characters), or -1 if s contains only ASCII characters
+func firstNonASCII(s string) (rune, int) {
+	pos := 0
+	for _, r := range s {
+		pos++
+		if r >= utf8.RuneSelf {
+			return r, pos
+		}
+	}
+	return 0, -1
+}
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"testing"
+)
+
+func Test_transliterateASCII(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "hello world", want: "hello world"},
+		{value: "José Müller", want: "Jose Muller"},
+		{value: "Straße", want: "Strasse"},
+		{value: "STRAẞE", want: "STRASSE"},
+		{value: "Øresund Ærø Œuvre", want: "Oresund Aero Oeuvre"},
+		{value: "Łódź Đakovo Þór", want: "Lodz Dakovo Thor"},
+		{value: "ﬁnancial ｆｕｌｌｗｉｄｔｈ x²", want: "financial fullwidth x2"},
+		{value: "Θεσσαλονίκη ΑΘΗΝΑ", want: "Thessaloniki ATHINA"},
+		{value: "Москва Жуков ЖУКОВ Щука", want: "Moskva Zhukov ZHUKOV Shchuka"},
+		{value: "Їжак Ґанок Євген", want: "Izhak Ganok Ievgen"},
+		{value: "Љубљана Ђорђе", want: "Ljubljana Djordje"},
+		{value: "объект", want: "obieekt"},
+		{value: "“quoted” ‘text’ «guillemets» – em—dash…", want: `"quoted" 'text' "guillemets" - em-dash...`},
+		{value: "5 €, 10 £ © ®", want: "5 EUR, 10 GBP (c) (r)"},
+		{value: "a b c", want: "a b c"},
+		{value: "日本語 text 😀", want: " text "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := transliterateASCII(tt.value); got != tt.want {
+				t.Errorf("transliterateASCII() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
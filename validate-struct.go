@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Name of the struct tag that contains the rule for a field
+const structTagName = "validate"
+
+// ValidateStruct validates and sanitizes in-place all fields of a struct that have a `validate` tag.
+// The parameter `val` must be a non-nil pointer to a struct.
+// Nested structs, pointers to structs, and slices and arrays of structs are walked recursively, even if they don't have a `validate` tag; structs that are reached more than once through pointers, such as in cyclic data structures, are walked only once.
+// Fields with the tag `validate:"-"` are skipped, and so are unexported fields, except embedded structs, whose exported fields are validated too.
+// Supported field types are: `string`, integers, floats, slices, arrays, and maps (with string keys) of those types at any level of nesting, types whose underlying type is one of those, types registered with RegisterType, pointers to those types, and interfaces containing values of those types (nil pointers and interfaces are skipped).
+// Rules for arrays can't include "unique", as that could change the number of elements.
+// Validation stops at the first field that fails; use ValidateStructAll to collect the errors for all fields.
+func ValidateStruct(val any) error {
+	return validateStruct(val, false)
+}
+
+// ValidateStructAll is like ValidateStruct, but it doesn't stop at the first field that fails validation.
+// If any field fails validation, it returns a ValidationErrors with the errors for all fields, sorted by field order.
+func ValidateStructAll(val any) error {
+	return validateStruct(val, true)
+}
+
+// validateStruct implements ValidateStruct and ValidateStructAll
+func validateStruct(val any, allErrors bool) error {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("value must be a non-nil pointer to a struct, got %T", val)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("value must be a non-nil pointer to a struct, got %T", val)
+	}
+
+	sv := &structValidator{
+		allErrors: allErrors,
+		visited:   map[visitedStruct]struct{}{},
+	}
+	sv.visit(rv.Addr())
+	err := sv.validateStructValue(rv, "")
+	if err != nil {
+		return err
+	}
+	if len(sv.errs) > 0 {
+		return sv.errs
+	}
+	return nil
+}
+
+// structValidator walks through the fields of a struct to validate them
+type structValidator struct {
+	// If true, collects all errors rather than stopping at the first one
+	allErrors bool
+	errs      ValidationErrors
+	// Structs that were already reached through a pointer
+	visited map[visitedStruct]struct{}
+}
+
+// visitedStruct is the key for structValidator.visited
+// The type is included because a struct and its first field have the same address
+type visitedStruct struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// visit records that the struct pointed by ptr is being walked, and returns false if it was already
+func (sv *structValidator) visit(ptr reflect.Value) bool {
+	key := visitedStruct{ptr: ptr.Pointer(), typ: ptr.Type()}
+	if _, ok := sv.visited[key]; ok {
+		return false
+	}
+	sv.visited[key] = struct{}{}
+	return true
+}
+
+// handleError returns err if validation should stop; when collecting all errors, it records err and returns nil
+func (sv *structValidator) handleError(err error) error {
+	if err == nil || !sv.allErrors {
+		return err
+	}
+	sv.errs = sv.errs.append(err)
+	return nil
+}
+
+// validateStructValue validates all fields of the struct rv
+// The parameter `path` contains the path of the struct, used in error messages
+func (sv *structValidator) validateStructValue(rv reflect.Value, path string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			// Exported fields of embedded structs are promoted, so they're validated even if the embedded type is unexported
+			if field.Anonymous && isStructType(field.Type) {
+				err := sv.validateStructField(rv.Field(i), "", false, joinFieldPath(path, field.Name))
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		rule, hasRule := field.Tag.Lookup(structTagName)
+		if rule == "-" {
+			continue
+		}
+
+		err := sv.validateStructField(rv.Field(i), rule, hasRule, joinFieldPath(path, field.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateStructField validates a single field, walking into structs, pointers, slices, and arrays as needed
+func (sv *structValidator) validateStructField(fv reflect.Value, rule string, hasRule bool, path string) error {
+	// Types registered with RegisterType are always validated as values
+	_, isCustom := getCustomType(fv.Type())
+
+	switch kind := fv.Kind(); {
+	case isCustom:
+		// Validated below
+	case kind == reflect.Pointer:
+		if fv.IsNil() {
+			return nil
+		}
+		// Walk each struct only once, so cyclic data structures don't cause an infinite recursion
+		if isStructType(fv.Type()) && !sv.visit(fv) {
+			return nil
+		}
+		return sv.validateStructField(fv.Elem(), rule, hasRule, path)
+	case kind == reflect.Interface:
+		if fv.IsNil() {
+			return nil
+		}
+		elem := fv.Elem()
+		if elem.Kind() == reflect.Pointer {
+			// The value pointed to can be changed in-place
+			return sv.validateStructField(elem, rule, hasRule, path)
+		}
+		// Values in interfaces can't be changed in-place, so validate a copy and set it back
+		cp := reflect.New(elem.Type()).Elem()
+		cp.Set(elem)
+		n := len(sv.errs)
+		err := sv.validateStructField(cp, rule, hasRule, path)
+		if err != nil || len(sv.errs) > n {
+			return err
+		}
+		fv.Set(cp)
+		return nil
+	case kind == reflect.Struct:
+		return sv.validateStructValue(fv, path)
+	case kind == reflect.Slice || kind == reflect.Array:
+		// Slices and arrays of structs (or pointers to structs) are walked element by element
+		if isStructType(fv.Type().Elem()) {
+			for i := 0; i < fv.Len(); i++ {
+				err := sv.validateStructField(fv.Index(i), "", false, fmt.Sprintf("%s[%d]", path, i))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	// Fields without a rule are left unchanged
+	if !hasRule {
+		return nil
+	}
+
+	err := validateStructFieldValue(fv, rule)
+	if err != nil {
+		return sv.handleError(prependErrorPath(err, path))
+	}
+	return nil
+}
+
+// validateStructFieldValue validates the value of a field and sets the sanitized result
+func validateStructFieldValue(fv reflect.Value, rule string) error {
+	res, err := validateValue(fv, rule)
+	if err != nil {
+		return err
+	}
+	fv.Set(res)
+
+	return nil
+}
+
+// joinFieldPath returns the path of the field with the given name in the struct at path
+func joinFieldPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// isStructType returns true if t is a struct or a pointer to a struct, and it's not a type registered with RegisterType
+func isStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if _, ok := getCustomType(t); ok {
+		return false
+	}
+	return t.Kind() == reflect.Struct
+}
@@ -0,0 +1,271 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateStruct(t *testing.T) {
+	type tags []string
+	type inner struct {
+		Name   string `validate:"max=5"`
+		NoRule string
+	}
+	type testStruct struct {
+		Name     string            `validate:"min=2"`
+		NamePtr  *string           `validate:"max=10"`
+		Tags     []string          `validate:"value=(asciionly),unique"`
+		Named    tags              `validate:"sort"`
+		Labels   map[string]string `validate:"key=(replace-whitespaces)"`
+		Skipped  string            `validate:"-"`
+		NoRule   string
+		Inner    inner
+		InnerPtr *inner
+		Items    []inner
+		ItemPtrs []*inner
+		Count    int     `validate:"max=10,clamp"`
+		Price    float64 `validate:"min=0"`
+		private  string  `validate:"max=1"`
+	}
+
+	strPtr := func(s string) *string {
+		return &s
+	}
+
+	tests := []struct {
+		name    string
+		val     testStruct
+		wantRes testStruct
+		wantErr bool
+	}{
+		{
+			name:    "empty struct",
+			val:     testStruct{},
+			wantRes: testStruct{},
+		},
+		{
+			name: "sanitize all fields",
+			val: testStruct{
+				Name:     "  hello  world ",
+				NamePtr:  strPtr(" ciao "),
+				Tags:     []string{"b", "a😀", "b"},
+				Named:    tags{"z", "y"},
+				Labels:   map[string]string{"foo bar": " baz "},
+				Skipped:  "  skipped  ",
+				NoRule:   "  norule  ",
+				Inner:    inner{Name: " hi ", NoRule: " x "},
+				InnerPtr: &inner{Name: " hey "},
+				Items:    []inner{{Name: " 1 "}, {Name: " 2 "}},
+				ItemPtrs: []*inner{{Name: " 3 "}, nil},
+				Count:    20,
+				Price:    1.5,
+				private:  "  private  ",
+			},
+			wantRes: testStruct{
+				Name:     "hello world",
+				NamePtr:  strPtr("ciao"),
+				Tags:     []string{"a", "b"},
+				Named:    tags{"y", "z"},
+				Labels:   map[string]string{"foo_bar": "baz"},
+				Skipped:  "  skipped  ",
+				NoRule:   "  norule  ",
+				Inner:    inner{Name: "hi", NoRule: " x "},
+				InnerPtr: &inner{Name: "hey"},
+				Items:    []inner{{Name: "1"}, {Name: "2"}},
+				ItemPtrs: []*inner{{Name: "3"}, nil},
+				Count:    10,
+				Price:    1.5,
+				private:  "  private  ",
+			},
+		},
+		{
+			name:    "invalid numeric field",
+			val:     testStruct{Price: -1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid top-level field",
+			val:     testStruct{Name: "a"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid nested field",
+			val:     testStruct{Inner: inner{Name: "hello world"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid field in slice of structs",
+			val:     testStruct{ItemPtrs: []*inner{{Name: "ok"}, {Name: "hello world"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStruct(&tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStruct() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.val, tt.wantRes) {
+				t.Errorf("ValidateStruct() = %v, want %v", tt.val, tt.wantRes)
+			}
+		})
+	}
+
+	t.Run("error message contains the field path", func(t *testing.T) {
+		val := testStruct{Items: []inner{{Name: "ok"}, {Name: "hello world"}}}
+		err := ValidateStruct(&val)
+		if err == nil {
+			t.Fatal("ValidateStruct() expected an error")
+		}
+		if err.Error() != "Items[1].Name: value is longer than 5" {
+			t.Errorf("ValidateStruct() error = %v", err)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		var nilPtr *testStruct
+		for _, val := range []any{nil, testStruct{}, nilPtr, strPtr("foo")} {
+			err := ValidateStruct(val)
+			if err == nil {
+				t.Errorf("ValidateStruct(%T) expected an error", val)
+			}
+		}
+	})
+
+	t.Run("unsupported field type", func(t *testing.T) {
+		val := struct {
+			Value complex128 `validate:"min=1"`
+		}{Value: 1}
+		err := ValidateStruct(&val)
+		if err == nil {
+			t.Error("ValidateStruct() expected an error")
+		}
+	})
+
+	t.Run("cyclic pointers", func(t *testing.T) {
+		type node struct {
+			Name     string `validate:"max=5"`
+			Parent   *node
+			Children []*node
+		}
+		root := &node{Name: " root "}
+		child := &node{Name: " child ", Parent: root}
+		root.Children = []*node{child, child}
+		child.Children = []*node{root}
+
+		err := ValidateStruct(root)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+		if root.Name != "root" || child.Name != "child" {
+			t.Errorf("ValidateStruct() names = %q, %q", root.Name, child.Name)
+		}
+
+		child.Name = "too long"
+		err = ValidateStruct(root)
+		if err == nil || err.Error() != "Children[0].Name: value is longer than 5" {
+			t.Errorf("ValidateStruct() error = %v", err)
+		}
+	})
+
+	t.Run("embedded unexported struct", func(t *testing.T) {
+		type embedded struct {
+			E string `validate:"max=2"`
+		}
+		type embeddedPtr struct {
+			P string `validate:"max=2"`
+		}
+		type outer struct {
+			embedded
+			*embeddedPtr
+		}
+
+		val := outer{embedded: embedded{E: " ab "}, embeddedPtr: &embeddedPtr{P: " cd "}}
+		err := ValidateStruct(&val)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+		if val.E != "ab" || val.P != "cd" {
+			t.Errorf("ValidateStruct() = %q, %q", val.E, val.P)
+		}
+
+		val = outer{embedded: embedded{E: "abc"}}
+		err = ValidateStruct(&val)
+		if err == nil || err.Error() != "embedded.E: value is longer than 2" {
+			t.Errorf("ValidateStruct() error = %v", err)
+		}
+	})
+
+	t.Run("interface fields", func(t *testing.T) {
+		type withInterface struct {
+			Value any `validate:"max=5"`
+			Inner any
+		}
+
+		val := withInterface{}
+		err := ValidateStruct(&val)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+
+		str := " hi "
+		val = withInterface{Value: " hello ", Inner: &inner{Name: " x "}}
+		err = ValidateStruct(&val)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+		if !reflect.DeepEqual(val, withInterface{Value: "hello", Inner: &inner{Name: "x"}}) {
+			t.Errorf("ValidateStruct() = %v", val)
+		}
+
+		val = withInterface{Value: &str}
+		err = ValidateStruct(&val)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+		if str != "hi" {
+			t.Errorf("ValidateStruct() = %q", str)
+		}
+
+		val = withInterface{Value: "hello world"}
+		err = ValidateStruct(&val)
+		if err == nil || err.Error() != "Value: value is longer than 5" {
+			t.Errorf("ValidateStruct() error = %v", err)
+		}
+		if val.Value != "hello world" {
+			t.Errorf("ValidateStruct() changed the value on error: %q", val.Value)
+		}
+	})
+
+	t.Run("array fields", func(t *testing.T) {
+		type withArray struct {
+			Names  [2]string `validate:"value=(max=5),sort"`
+			Counts [3]int    `validate:"value=(max=10,clamp)"`
+		}
+
+		val := withArray{Names: [2]string{" b ", "a"}, Counts: [3]int{1, 20, 3}}
+		err := ValidateStruct(&val)
+		if err != nil {
+			t.Fatalf("ValidateStruct() error = %v", err)
+		}
+		if !reflect.DeepEqual(val, withArray{Names: [2]string{"a", "b"}, Counts: [3]int{1, 10, 3}}) {
+			t.Errorf("ValidateStruct() = %v", val)
+		}
+
+		val = withArray{Names: [2]string{"hello world", "a"}}
+		err = ValidateStruct(&val)
+		if err == nil || err.Error() != "Names[0]: value is longer than 5" {
+			t.Errorf("ValidateStruct() error = %v", err)
+		}
+
+		unique := struct {
+			Names [2]string `validate:"unique"`
+		}{Names: [2]string{"a", "a"}}
+		err = ValidateStruct(&unique)
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("ValidateStruct() error = %v, want %v", err, ErrInvalidParameter)
+		}
+	})
+}
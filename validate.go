@@ -1,100 +1,76 @@
 package validator
 
 import (
-	"fmt"
 	"reflect"
 	"strings"
-	"sync"
 )
 
-type validateTypes interface {
-	string | map[string]string | []string
-}
-
-var validators sync.Map
-
 // Validate and sanitize a value, using generics to define the supported types.
+// Supported types are: `string`, integers, floats, slices, arrays, and maps (with string keys) of those types at any level of nesting, types whose underlying type is one of those, and types with a validator registered with RegisterType.
+// Validating a value of any other type returns an error that wraps ErrUnsupportedType.
 // The parameter `rule` follows the format for the given type.
-func Validate[T validateTypes](val T, rule string) (res T, err error) {
-	var zero T
-	if reflect.ValueOf(val).IsZero() {
-		return zero, nil
-	}
-
+func Validate[T any](val T, rule string) (res T, err error) {
 	rule = strings.TrimSpace(rule)
 
-	var ok bool
+	var zero T
 	switch x := any(val).(type) {
 	case string:
-		cacheKey := "string|" + rule
-		f, _ := validators.Load(cacheKey)
-		var fT validator[string]
-		if f != nil {
-			fT, ok = f.(validator[string])
-			if !ok {
-				fT = nil
-			}
-		}
-		if fT == nil {
-			fT = stringValidator(rule)
-			defer validators.Store(cacheKey, fT)
+		if x == "" {
+			return zero, nil
 		}
-		x, err = fT(x)
-		if err != nil {
-			return zero, err
-		}
-		return any(x).(T), nil
+		return castResult[T](cachedValidator(rule, stringValidator)(x))
 	case []string:
 		if len(x) == 0 {
 			return val, nil
 		}
-		cacheKey := "[]string|" + rule
-		f, _ := validators.Load(cacheKey)
-		var fT validator[[]string]
-		if f != nil {
-			fT, ok = f.(validator[[]string])
-			if !ok {
-				fT = nil
-			}
-		}
-		if fT == nil {
-			fT = sliceValidator[string](rule)
-			defer validators.Store(cacheKey, fT)
-		}
-		x, err = fT(x)
-		if err != nil {
-			return zero, err
-		}
-		return any(x).(T), nil
+		return castResult[T](cachedValidator(rule, sliceValidator[string])(x))
 	case map[string]string:
 		if len(x) == 0 {
 			return val, nil
 		}
-		cacheKey := "map[string]string|" + rule
-		f, _ := validators.Load(cacheKey)
-		var fT validator[map[string]string]
-		if f != nil {
-			fT, ok = f.(validator[map[string]string])
-			if !ok {
-				fT = nil
-			}
-		}
-		if fT == nil {
-			fT = mapValidator[string](rule)
-			defer validators.Store(cacheKey, fT)
-		}
-		x, err = fT(x)
+		return castResult[T](cachedValidator(rule, mapValidator[string])(x))
+
+	// Numbers are validated even when they're zero, as rules such as "nonzero" and "min" apply to them
+	case int:
+		return castResult[T](cachedValidator(rule, numberValidator[int])(x))
+	case int8:
+		return castResult[T](cachedValidator(rule, numberValidator[int8])(x))
+	case int16:
+		return castResult[T](cachedValidator(rule, numberValidator[int16])(x))
+	case int32:
+		return castResult[T](cachedValidator(rule, numberValidator[int32])(x))
+	case int64:
+		return castResult[T](cachedValidator(rule, numberValidator[int64])(x))
+	case uint:
+		return castResult[T](cachedValidator(rule, numberValidator[uint])(x))
+	case uint8:
+		return castResult[T](cachedValidator(rule, numberValidator[uint8])(x))
+	case uint16:
+		return castResult[T](cachedValidator(rule, numberValidator[uint16])(x))
+	case uint32:
+		return castResult[T](cachedValidator(rule, numberValidator[uint32])(x))
+	case uint64:
+		return castResult[T](cachedValidator(rule, numberValidator[uint64])(x))
+	case float32:
+		return castResult[T](cachedValidator(rule, numberValidator[float32])(x))
+	case float64:
+		return castResult[T](cachedValidator(rule, numberValidator[float64])(x))
+
+	default:
+		// Other types, including registered types, nested collections, and named types
+		skip, err := skipValidation(reflect.ValueOf(&val).Elem())
 		if err != nil {
 			return zero, err
 		}
-		return any(x).(T), nil
-	default:
-		return zero, fmt.Errorf("cannot find a validator for type %T", val)
+		if skip {
+			return val, nil
+		}
+		return cachedValidator(rule, typeValidator[T])(val)
 	}
 }
 
 // ValidateAny validates and sanitizes a value with type any.
-// Supported types are: `string`, `map[string]string`, `[]string`, and pointers to those types.
+// Supported types are: `string`, integers, floats, slices, arrays, and maps (with string keys) of those types at any level of nesting, types whose underlying type is one of those, types registered with RegisterType, and pointers to those types.
 // The parameter `rule` follows the format for the given type.
 func ValidateAny(val any, rule string) (res any, err error) {
 	if val == nil {
@@ -115,35 +91,111 @@ func ValidateAny(val any, rule string) (res any, err error) {
 	// Switch based on the type of the value
 	switch x := val.(type) {
 	case string:
-		x, err = Validate(x, rule)
-		if err != nil {
-			return nil, err
-		}
-		if isPtr {
-			return &x, nil
-		}
-		return x, nil
+		return validateAnyValue(x, rule, isPtr)
 	case []string:
-		x, err = Validate(x, rule)
-		if err != nil {
-			return nil, err
-		}
-		if isPtr {
-			return &x, nil
-		}
-		return x, nil
+		return validateAnyValue(x, rule, isPtr)
 	case map[string]string:
-		x, err = Validate(x, rule)
+		return validateAnyValue(x, rule, isPtr)
+	case int:
+		return validateAnyValue(x, rule, isPtr)
+	case int8:
+		return validateAnyValue(x, rule, isPtr)
+	case int16:
+		return validateAnyValue(x, rule, isPtr)
+	case int32:
+		return validateAnyValue(x, rule, isPtr)
+	case int64:
+		return validateAnyValue(x, rule, isPtr)
+	case uint:
+		return validateAnyValue(x, rule, isPtr)
+	case uint8:
+		return validateAnyValue(x, rule, isPtr)
+	case uint16:
+		return validateAnyValue(x, rule, isPtr)
+	case uint32:
+		return validateAnyValue(x, rule, isPtr)
+	case uint64:
+		return validateAnyValue(x, rule, isPtr)
+	case float32:
+		return validateAnyValue(x, rule, isPtr)
+	case float64:
+		return validateAnyValue(x, rule, isPtr)
+	default:
+		// Other types, including nested collections and named types, are validated using reflection
+		res, err := validateValue(reflect.ValueOf(val), rule)
 		if err != nil {
 			return nil, err
 		}
 		if isPtr {
-			return &x, nil
+			ptr := reflect.New(res.Type())
+			ptr.Elem().Set(res)
+			return ptr.Interface(), nil
 		}
-		return x, nil
-	default:
-		return nil, fmt.Errorf("cannot find a validator for type %T", val)
+		return res.Interface(), nil
+	}
+}
+
+// validateAnyValue validates a value for ValidateAny, returning a pointer to the result if isPtr is true
+func validateAnyValue[T any](val T, rule string, isPtr bool) (any, error) {
+	res, err := Validate(val, rule)
+	if err != nil {
+		return nil, err
+	}
+	if isPtr {
+		return &res, nil
+	}
+	return res, nil
+}
+
+// castResult converts the result of a validator of type X to type T, which must be the same type
+func castResult[T any, X any](val X, err error) (T, error) {
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return any(val).(T), nil
+}
+
+// validatorCacheKey is the key for the validators cache
+type validatorCacheKey struct {
+	typ     reflect.Type
+	rule    string
+	reflect bool
+}
+
+// cachedValidator returns the validator for type T and the given rule, loading it from the cache if possible
+// If the validator is not in the cache, it's created with the factory function and stored in the cache
+func cachedValidator[T any](rule string, factory func(rule string) (validator[T], error)) validator[T] {
+	key := validatorCacheKey{
+		typ:  reflect.TypeOf((*T)(nil)).Elem(),
+		rule: rule,
 	}
+	return loadOrStoreValidator(key, factory)
+}
+
+// cachedReflectValidator returns the validator for values of type t (using reflection) and the given rule, loading it from the cache if possible
+func cachedReflectValidator(t reflect.Type, rule string) validator[reflect.Value] {
+	key := validatorCacheKey{
+		typ:     t,
+		rule:    rule,
+		reflect: true,
+	}
+	return loadOrStoreValidator(key, func(rule string) (validator[reflect.Value], error) {
+		return reflectValidator(t, rule)
+	})
+}
+
+// loadOrStoreValidator returns the validator with the given key from the cache, creating it with the factory function if needed
+// If the factory returns an error, the validator that is stored in the cache returns that error
+func loadOrStoreValidator[T any](key validatorCacheKey, factory func(rule string) (validator[T], error)) validator[T] {
+	f := validators.getOrCreate(key, func() any {
+		fT, err := factory(key.rule)
+		if err != nil {
+			return errorValidateFunc[T](err)
+		}
+		return fT
+	})
+	return f.(validator[T])
 }
 
 // validator is the type of a validator function
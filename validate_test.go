@@ -217,6 +217,118 @@ func TestValidate(t *testing.T) {
 		})
 	})
 
+	t.Run("numbers", func(t *testing.T) {
+		t.Run("Validate", func(t *testing.T) {
+			i, err := Validate(0, "min=1")
+			if err == nil {
+				t.Errorf("Validate() expected an error for zero value, got %v", i)
+			}
+			u, err := Validate(uint16(500), "max=100,clamp")
+			if err != nil || u != 100 {
+				t.Errorf("Validate() = %v, %v, want 100", u, err)
+			}
+			f, err := Validate(float32(2.5), "positive")
+			if err != nil || f != 2.5 {
+				t.Errorf("Validate() = %v, %v, want 2.5", f, err)
+			}
+		})
+		t.Run("ValidateAny", func(t *testing.T) {
+			tests := []struct {
+				name    string
+				val     any
+				rule    string
+				wantRes any
+				wantErr bool
+			}{
+				{name: "int", val: 5, rule: "min=1", wantRes: 5},
+				{name: "int8", val: int8(-5), rule: "min=-10", wantRes: int8(-5)},
+				{name: "int16", val: int16(5), rule: "", wantRes: int16(5)},
+				{name: "int32", val: int32(5), rule: "", wantRes: int32(5)},
+				{name: "int64", val: int64(5), rule: "max=3", wantErr: true},
+				{name: "uint", val: uint(5), rule: "", wantRes: uint(5)},
+				{name: "uint8", val: uint8(5), rule: "", wantRes: uint8(5)},
+				{name: "uint16", val: uint16(5), rule: "", wantRes: uint16(5)},
+				{name: "uint32", val: uint32(5), rule: "", wantRes: uint32(5)},
+				{name: "uint64", val: uint64(5), rule: "nonzero", wantRes: uint64(5)},
+				{name: "float32", val: float32(5), rule: "", wantRes: float32(5)},
+				{name: "float64", val: 5.5, rule: "max=5,clamp", wantRes: 5.0},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					gotRes, err := ValidateAny(tt.val, tt.rule)
+					if (err != nil) != tt.wantErr {
+						t.Errorf("ValidateAny() error = %v, wantErr %v", err, tt.wantErr)
+						return
+					}
+					if !reflect.DeepEqual(gotRes, tt.wantRes) {
+						t.Errorf("ValidateAny() = %v (%T), want %v (%T)", gotRes, gotRes, tt.wantRes, tt.wantRes)
+					}
+				})
+			}
+		})
+		t.Run("ValidateAny with pointer", func(t *testing.T) {
+			val := 42
+			gotRes, err := ValidateAny(&val, "max=10,clamp")
+			if err != nil {
+				t.Errorf("ValidateAny() error = %v", err)
+				return
+			}
+			gotResV, ok := gotRes.(*int)
+			if !ok {
+				t.Error("ValidateAny() did not return a pointer to int")
+				return
+			}
+			if *gotResV != 10 {
+				t.Errorf("ValidateAny() = %v, want %v", *gotResV, 10)
+			}
+		})
+	})
+
+	t.Run("nested collections", func(t *testing.T) {
+		t.Run("Validate", func(t *testing.T) {
+			gotRes, err := Validate([][]string{{" a "}}, "value=(value=(max=5))")
+			if err != nil {
+				t.Errorf("Validate() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, [][]string{{"a"}}) {
+				t.Errorf("Validate() = %v", gotRes)
+			}
+			emptyRes, err := Validate(map[string][]string{}, "min=1")
+			if err != nil || len(emptyRes) != 0 {
+				t.Errorf("Validate() = %v, %v; want empty map", emptyRes, err)
+			}
+		})
+		t.Run("ValidateAny", func(t *testing.T) {
+			type tags []string
+			val := map[string]tags{"a": {" b "}}
+			gotRes, err := ValidateAny(val, "value=(max=1)")
+			if err != nil {
+				t.Errorf("ValidateAny() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, map[string]tags{"a": {"b"}}) {
+				t.Errorf("ValidateAny() = %v (%T)", gotRes, gotRes)
+			}
+		})
+		t.Run("ValidateAny with pointer", func(t *testing.T) {
+			val := []map[string]string{{" a ": "b"}}
+			gotRes, err := ValidateAny(&val, "")
+			if err != nil {
+				t.Errorf("ValidateAny() error = %v", err)
+				return
+			}
+			gotResV, ok := gotRes.(*[]map[string]string)
+			if !ok {
+				t.Error("ValidateAny() did not return a pointer to []map[string]string")
+				return
+			}
+			if !reflect.DeepEqual(*gotResV, []map[string]string{{"a": "b"}}) {
+				t.Errorf("ValidateAny() = %v", *gotResV)
+			}
+		})
+	})
+
 	t.Run("ValidateAny cases", func(t *testing.T) {
 		var zeroStr string
 		tests := []struct {
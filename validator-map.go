@@ -1,85 +1,226 @@
 package validator
 
 import (
-	"errors"
-	"fmt"
-	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
 )
 
 // mapValidator returns a validator for type `map[string]T`
-func mapValidator[T any](rule string) validator[map[string]T] {
+func mapValidator[T any](rule string) (validator[map[string]T], error) {
 	var zero T
-	errFunc := errorValidateFunc[map[string]T]
+
+	// Values can be joined only if they're strings
+	var valueJoiner func([]T, string) T
+	if _, ok := any(zero).(string); ok {
+		valueJoiner = func(values []T, sep string) T {
+			strs := make([]string, len(values))
+			for i, v := range values {
+				strs[i] = any(v).(string)
+			}
+			return any(strings.Join(strs, sep)).(T)
+		}
+	}
+
+	return mapValidatorWith(rule, typeValidator[T], valueJoiner)
+}
+
+// mapValidatorWith returns a validator for type `map[string]T`, using valueValidatorFactory to create the validator for each value
+// The function valueJoiner joins values with a separator, and it's used by the "oncollision=join" rule; it can be nil if values of type T are not strings
+func mapValidatorWith[T any](rule string, valueValidatorFactory func(rule string) (validator[T], error), valueJoiner func([]T, string) T) (validator[map[string]T], error) {
 
 	// Parse rule
-	params, err := parseParams(rule)
+	rp, err := parseRule(rule)
 	if err != nil {
-		return errFunc(err)
+		return nil, err
 	}
+	params := rp.toMap()
 
 	// Rules from parameters
 	min := -1
 	if v, ok := params["min"]; ok && v != "" {
 		min, err = strconv.Atoi(v)
 		if err != nil {
-			return errFunc(fmt.Errorf("parameter 'min' is invalid: failed to cast to int: %v", err))
+			return nil, invalidParamError("min", "parameter 'min' is invalid: failed to cast to int: %v", err)
 		}
 		if min < 1 {
-			return errFunc(errors.New("parameter 'min' must be greater than 0"))
+			return nil, invalidParamError("min", "parameter 'min' must be greater than 0")
 		}
 	}
 	max := -1
 	if v, ok := params["max"]; ok && v != "" {
 		max, err = strconv.Atoi(v)
 		if err != nil {
-			return errFunc(fmt.Errorf("parameter 'max' is invalid: failed to cast to int: %v", err))
+			return nil, invalidParamError("max", "parameter 'max' is invalid: failed to cast to int: %v", err)
 		}
 		if max < 1 {
-			return errFunc(errors.New("parameter 'max' must be greater than 0"))
+			return nil, invalidParamError("max", "parameter 'max' must be greater than 0")
 		}
 	}
 	if max > 0 && min > max {
-		return errFunc(errors.New("parameter 'max' must not be smaller than parameter 'min'"))
+		return nil, invalidParamError("max", "parameter 'max' must not be smaller than parameter 'min'")
+	}
+
+	allErrors := false
+	if _, ok := params["all-errors"]; ok {
+		// Boolean option, with no value
+		allErrors = true
+	}
+
+	// Rules for keys that are the same after sanitization
+	collisionMode := ""
+	if v, ok := params["oncollision"]; ok {
+		collisionMode = strings.ToLower(v)
+		switch collisionMode {
+		case "error", "first", "last":
+			// All good
+		case "join":
+			if valueJoiner == nil {
+				return nil, invalidParamError("oncollision", "parameter 'oncollision=join' is only supported for maps of strings")
+			}
+		default:
+			return nil, invalidParamError("oncollision", "parameter 'oncollision' is invalid")
+		}
+	}
+	joinSep, ok := rp.scalar("joinsep")
+	if ok && collisionMode != "join" {
+		return nil, invalidParamError("joinsep", "parameter 'joinsep' requires 'oncollision=join'")
+	} else if !ok {
+		joinSep = ","
 	}
 
 	// Validator function for each key
-	keyValidator := stringValidator(params["key"])
+	keyValidator, err := stringValidator(params["key"])
+	if err != nil {
+		return nil, err
+	}
 
 	// Validator function for each value
-	var valueValidator validator[T]
-	var fp reflect.Value
-
-	switch any(zero).(type) {
-	case string:
-		f := stringValidator(params["value"])
-		fp = reflect.ValueOf(&valueValidator).Elem()
-		fp.Set(reflect.Indirect(reflect.ValueOf(f)))
+	valueValidator, err := valueValidatorFactory(params["value"])
+	if err != nil {
+		return nil, err
 	}
 
 	return func(val map[string]T) (map[string]T, error) {
+		// When collecting all errors, validation continues after the first failure
+		var errs ValidationErrors
+
 		// Check if we have rules
 		if min > 0 && len(val) < min {
-			return nil, fmt.Errorf("value is shorter than %d", min)
+			err := newValidationError(ErrTooShort, "min", params["min"], len(val), "value is shorter than %d", min)
+			if !allErrors {
+				return nil, err
+			}
+			errs = errs.append(err)
 		}
 		if max > 0 && len(val) > max {
-			return nil, fmt.Errorf("value is longer than %d", max)
+			err := newValidationError(ErrTooLong, "max", params["max"], len(val), "value is longer than %d", max)
+			if !allErrors {
+				return nil, err
+			}
+			errs = errs.append(err)
 		}
 
 		// Validate each item
+		// When collecting all errors or handling collisions, keys are sorted so the result is deterministic
+		var keys []string
+		if allErrors || collisionMode != "" {
+			keys = maps.Keys(val)
+			sort.Strings(keys)
+		}
 		res := make(map[string]T, len(val))
-		for k, v := range val {
-			k, err = keyValidator(k)
+		// Original key for each sanitized key, used to detect collisions
+		var originalKeys map[string]string
+		// Values for keys that collide, when joining them
+		var joinValues map[string][]T
+		if collisionMode != "" {
+			originalKeys = make(map[string]string, len(val))
+		}
+		validateItem := func(k string, v T) error {
+			key, err := keyValidator(k)
 			if err != nil {
-				return nil, err
+				return prependErrorPath(keyError(err), "["+strconv.Quote(k)+"]")
 			}
 			v, err = valueValidator(v)
 			if err != nil {
-				return nil, err
+				return prependErrorPath(err, "["+strconv.Quote(k)+"]")
+			}
+
+			// Check for collisions with keys that were already validated
+			if originalKeys != nil {
+				prev, ok := originalKeys[key]
+				if !ok {
+					originalKeys[key] = k
+				} else {
+					switch collisionMode {
+					case "error":
+						err = newValidationError(ErrKeyCollision, "oncollision", params["oncollision"], -1, "keys %q and %q are both %q after sanitization", prev, k, key)
+						return prependErrorPath(err, "["+strconv.Quote(k)+"]")
+					case "first":
+						return nil
+					case "join":
+						if joinValues == nil {
+							joinValues = map[string][]T{}
+						}
+						if _, ok := joinValues[key]; !ok {
+							joinValues[key] = []T{res[key]}
+						}
+						joinValues[key] = append(joinValues[key], v)
+						return nil
+					}
+				}
+			}
+
+			res[key] = v
+			return nil
+		}
+		if keys == nil {
+			for k, v := range val {
+				err := validateItem(k, v)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return res, nil
+		}
+
+		for _, k := range keys {
+			err := validateItem(k, val[k])
+			if err != nil {
+				if !allErrors {
+					return nil, err
+				}
+				errs = errs.append(err)
+			}
+		}
+		if len(errs) > 0 {
+			return nil, errs
+		}
+
+		// Join the values of keys that collide, then validate the result again
+		// Keys are sorted so errors are returned in a deterministic order
+		if len(joinValues) > 0 {
+			joinKeys := maps.Keys(joinValues)
+			sort.Strings(joinKeys)
+			for _, key := range joinKeys {
+				v, err := valueValidator(valueJoiner(joinValues[key], joinSep))
+				if err != nil {
+					err = prependErrorPath(err, "["+strconv.Quote(key)+"]")
+					if !allErrors {
+						return nil, err
+					}
+					errs = errs.append(err)
+					continue
+				}
+				res[key] = v
+			}
+			if len(errs) > 0 {
+				return nil, errs
 			}
-			res[k] = v
 		}
 
 		return res, nil
-	}
+	}, nil
 }
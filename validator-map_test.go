@@ -1,7 +1,9 @@
 package validator
 
 import (
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -59,8 +61,11 @@ func Test_mapValidator(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validator := mapValidator[string](tt.rule)
-			gotRes, err := validator(tt.value)
+			validator, err := mapValidator[string](tt.rule)
+			var gotRes map[string]string
+			if err == nil {
+				gotRes, err = validator(tt.value)
+			}
 			if (err != nil) != tt.wantErr {
 				t.Errorf("mapValidator().validator error = %v, wantErr %v (value = %s)", err, tt.wantErr, gotRes)
 				return
@@ -71,3 +76,71 @@ func Test_mapValidator(t *testing.T) {
 		})
 	}
 }
+
+func Test_mapValidatorCollision(t *testing.T) {
+	value := map[string]string{"Env": "prod", " Env ": "dev", "ENV  ": "test", "region": "eu"}
+	tests := []struct {
+		name       string
+		rule       string
+		value      map[string]string
+		wantRes    map[string]string
+		wantErr    error
+		wantErrMsg string
+	}{
+		{name: "no collisions", rule: "oncollision=error", value: map[string]string{"a": "1", " b ": "2"}, wantRes: map[string]string{"a": "1", "b": "2"}},
+		{name: "error", rule: "oncollision=error", value: value, wantErr: ErrKeyCollision, wantErrMsg: `keys " Env " and "Env" are both "Env" after sanitization`},
+		{name: "error with all-errors", rule: "oncollision=error,key=(case=lower),all-errors", value: value, wantErr: ErrKeyCollision, wantErrMsg: `keys " Env " and "ENV  " are both "env" after sanitization`},
+		{name: "first", rule: "oncollision=first", value: value, wantRes: map[string]string{"Env": "dev", "ENV": "test", "region": "eu"}},
+		{name: "last", rule: "oncollision=last", value: value, wantRes: map[string]string{"Env": "prod", "ENV": "test", "region": "eu"}},
+		{name: "first with key rules", rule: "oncollision=first,key=(case=lower)", value: value, wantRes: map[string]string{"env": "dev", "region": "eu"}},
+		{name: "last with key rules", rule: "oncollision=last,key=(case=lower)", value: value, wantRes: map[string]string{"env": "prod", "region": "eu"}},
+		{name: "join", rule: "oncollision=join,key=(case=lower)", value: value, wantRes: map[string]string{"env": "dev,test,prod", "region": "eu"}},
+		{name: "join with separator", rule: "oncollision=join,joinsep=' | '", value: value, wantRes: map[string]string{"Env": "dev | prod", "ENV": "test", "region": "eu"}},
+		{name: "join validates the result", rule: "oncollision=join,value=(max=5)", value: value, wantErr: ErrTooLong, wantErrMsg: `["Env"]`},
+		{name: "join truncates the result", rule: "oncollision=join,value=(max=5,truncate)", value: value, wantRes: map[string]string{"Env": "dev,p", "ENV": "test", "region": "eu"}},
+		{name: "invalid oncollision", rule: "oncollision=merge", value: value, wantErr: ErrInvalidParameter},
+		{name: "joinsep without join", rule: "oncollision=first,joinsep=;", value: value, wantErr: ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := mapValidator[string](tt.rule)
+			var gotRes map[string]string
+			if err == nil {
+				gotRes, err = validator(tt.value)
+			}
+			if !errors.Is(err, tt.wantErr) || (err != nil && tt.wantErr == nil) {
+				t.Errorf("mapValidator().validator error = %v, wantErr %v (value = %s)", err, tt.wantErr, gotRes)
+				return
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantErrMsg) {
+				t.Errorf("mapValidator().validator error = %v, want message containing %q", err, tt.wantErrMsg)
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("mapValidator().validator = %v, want %v", gotRes, tt.wantRes)
+			}
+		})
+	}
+
+	t.Run("join with named types", func(t *testing.T) {
+		type label string
+		validator, err := typeValidator[map[string]label]("oncollision=join")
+		if err != nil {
+			t.Fatalf("typeValidator() error = %v", err)
+		}
+		got, err := validator(map[string]label{"a": "x", " a": "y"})
+		if err != nil {
+			t.Fatalf("validator() error = %v", err)
+		}
+		want := map[string]label{"a": "y,x"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("validator() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("join not supported", func(t *testing.T) {
+		_, err := mapValidator[int]("oncollision=join")
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("mapValidator() error = %v, want %v", err, ErrInvalidParameter)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package validator
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+
+	"golang.org/x/exp/constraints"
+)
+
+// numberTypes is the list of numeric types that can be validated
+type numberTypes interface {
+	constraints.Integer | constraints.Float
+}
+
+// numberValidator returns a validator for numeric types
+func numberValidator[T numberTypes](rule string) (validator[T], error) {
+	var zero T
+	kind := reflect.TypeOf(zero).Kind()
+	isFloat := kind == reflect.Float32 || kind == reflect.Float64
+
+	// Parse rule
+	params, err := parseParams(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse parameters
+	var (
+		min, max       T
+		hasMin, hasMax bool
+		step           T
+	)
+	if v, ok := params["min"]; ok && v != "" {
+		min, err = parseNumber[T](v)
+		if err != nil {
+			return nil, invalidParamError("min", "parameter 'min' is invalid: failed to cast to %T: %v", zero, err)
+		}
+		hasMin = true
+	}
+	if v, ok := params["max"]; ok && v != "" {
+		max, err = parseNumber[T](v)
+		if err != nil {
+			return nil, invalidParamError("max", "parameter 'max' is invalid: failed to cast to %T: %v", zero, err)
+		}
+		hasMax = true
+	}
+	if hasMin && hasMax && min > max {
+		return nil, invalidParamError("max", "parameter 'max' must not be smaller than parameter 'min'")
+	}
+	if v, ok := params["step"]; ok && v != "" {
+		step, err = parseNumber[T](v)
+		if err != nil {
+			return nil, invalidParamError("step", "parameter 'step' is invalid: failed to cast to %T: %v", zero, err)
+		}
+		if !(step > 0) {
+			return nil, invalidParamError("step", "parameter 'step' must be greater than 0")
+		}
+	}
+	positive := false
+	if _, ok := params["positive"]; ok {
+		// Boolean option, with no value
+		positive = true
+	}
+	nonzero := false
+	if _, ok := params["nonzero"]; ok {
+		// Boolean option, with no value
+		nonzero = true
+	}
+	finite := false
+	if _, ok := params["finite"]; ok {
+		// Boolean option, with no value
+		// This has no effect on integers, which are always finite
+		finite = isFloat
+	}
+	clamp := false
+	if _, ok := params["clamp"]; ok {
+		// Boolean option, with no value
+		if !hasMin && !hasMax {
+			return nil, invalidParamError("clamp", "parameter 'clamp' requires 'min' and/or 'max'")
+		}
+		clamp = true
+	}
+
+	// Function that checks if a value is a multiple of step
+	// The base for the steps is the value of min, if set, or 0 otherwise
+	var isStep func(val T) bool
+	if step > 0 {
+		switch {
+		case isFloat:
+			isStep = func(val T) bool {
+				// Allow a small tolerance for rounding errors
+				r := math.Abs(math.Remainder(float64(val)-float64(min), float64(step)))
+				return r <= float64(step)*1e-9
+			}
+		case isSignedKind(kind):
+			// Compare the remainders rather than subtracting min, which could overflow
+			isStep = func(val T) bool {
+				return floorMod(int64(val), int64(step)) == floorMod(int64(min), int64(step))
+			}
+		default:
+			isStep = func(val T) bool {
+				return uint64(val)%uint64(step) == uint64(min)%uint64(step)
+			}
+		}
+	}
+
+	return func(val T) (res T, err error) {
+		if isFloat {
+			f := float64(val)
+			if math.IsNaN(f) {
+				// NaN can't be compared with other numbers, so it's rejected if there are range, nonzero, or step rules too
+				if finite || hasMin || hasMax || positive || nonzero || isStep != nil {
+					return zero, newValidationError(ErrNotFinite, "finite", "", -1, "value is not a number")
+				}
+				return val, nil
+			}
+			if math.IsInf(f, 0) {
+				if finite {
+					return zero, newValidationError(ErrNotFinite, "finite", "", -1, "value is not finite")
+				}
+				// Infinite values are not a multiple of any step
+				if isStep != nil {
+					return zero, newValidationError(ErrNotFinite, "step", params["step"], -1, "value is not finite")
+				}
+			}
+		}
+
+		// Check if we have range rules
+		if hasMin && val < min {
+			if !clamp {
+				return zero, newValidationError(ErrTooSmall, "min", params["min"], -1, "value is smaller than %v", min)
+			}
+			val = min
+		}
+		if hasMax && val > max {
+			if !clamp {
+				return zero, newValidationError(ErrTooLarge, "max", params["max"], -1, "value is greater than %v", max)
+			}
+			val = max
+		}
+		if positive && !(val > 0) {
+			return zero, newValidationError(ErrNotPositive, "positive", "", -1, "value is not positive")
+		}
+		if nonzero && val == 0 {
+			return zero, newValidationError(ErrZero, "nonzero", "", -1, "value is zero")
+		}
+		if isStep != nil && !isStep(val) {
+			return zero, newValidationError(ErrStep, "step", params["step"], -1, "value is not a multiple of %v", step)
+		}
+
+		return val, nil
+	}, nil
+}
+
+// parseNumber parses a string into a number of type T
+func parseNumber[T numberTypes](str string) (T, error) {
+	var zero T
+	rt := reflect.TypeOf(zero)
+	switch kind := rt.Kind(); {
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		f, err := strconv.ParseFloat(str, rt.Bits())
+		if err != nil {
+			return zero, err
+		}
+		return T(f), nil
+	case isSignedKind(kind):
+		i, err := strconv.ParseInt(str, 10, rt.Bits())
+		if err != nil {
+			return zero, err
+		}
+		return T(i), nil
+	default:
+		u, err := strconv.ParseUint(str, 10, rt.Bits())
+		if err != nil {
+			return zero, err
+		}
+		return T(u), nil
+	}
+}
+
+// isSignedKind returns true if the kind is a signed integer
+func isSignedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// floorMod returns the remainder of x divided by m, in the range [0, m)
+// m must be greater than 0
+func floorMod(x int64, m int64) int64 {
+	r := x % m
+	if r < 0 {
+		r += m
+	}
+	return r
+}
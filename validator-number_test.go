@@ -0,0 +1,214 @@
+package validator
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_numberValidator(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   int
+			wantRes int
+			wantErr bool
+		}{
+			{name: "no rules", rule: "", value: 42, wantRes: 42},
+			{name: "zero", rule: "", value: 0, wantRes: 0},
+			{name: "min ok", rule: "min=1", value: 1, wantRes: 1},
+			{name: "min fail", rule: "min=1", value: 0, wantErr: true},
+			{name: "negative min ok", rule: "min=-5", value: -5, wantRes: -5},
+			{name: "max ok", rule: "max=10", value: 10, wantRes: 10},
+			{name: "max fail", rule: "max=10", value: 11, wantErr: true},
+			{name: "min and max ok", rule: "min=1,max=10", value: 5, wantRes: 5},
+			{name: "clamp to min", rule: "min=1,max=10,clamp", value: -3, wantRes: 1},
+			{name: "clamp to max", rule: "min=1,max=10,clamp", value: 30, wantRes: 10},
+			{name: "clamp in range", rule: "min=1,max=10,clamp", value: 4, wantRes: 4},
+			{name: "step ok", rule: "step=5", value: 15, wantRes: 15},
+			{name: "step fail", rule: "step=5", value: 16, wantErr: true},
+			{name: "step with min ok", rule: "min=1,step=5", value: 11, wantRes: 11},
+			{name: "step with min fail", rule: "min=1,step=5", value: 10, wantErr: true},
+			{name: "positive ok", rule: "positive", value: 1, wantRes: 1},
+			{name: "positive fail zero", rule: "positive", value: 0, wantErr: true},
+			{name: "positive fail negative", rule: "positive", value: -1, wantErr: true},
+			{name: "nonzero ok", rule: "nonzero", value: -1, wantRes: -1},
+			{name: "nonzero fail", rule: "nonzero", value: 0, wantErr: true},
+			{name: "finite has no effect", rule: "finite", value: 3, wantRes: 3},
+
+			{name: "invalid rule: min not a number", rule: "min=a", value: 1, wantErr: true},
+			{name: "invalid rule: min is a float", rule: "min=1.5", value: 1, wantErr: true},
+			{name: "invalid rule: min>max", rule: "min=5,max=1", value: 1, wantErr: true},
+			{name: "invalid rule: step<1", rule: "step=0", value: 1, wantErr: true},
+			{name: "invalid rule: clamp without range", rule: "clamp", value: 1, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := numberValidator[int](tt.rule)
+				var gotRes int
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("numberValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("numberValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("uint8", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   uint8
+			wantRes uint8
+			wantErr bool
+		}{
+			{name: "no rules", rule: "", value: 200, wantRes: 200},
+			{name: "clamp", rule: "max=100,clamp", value: 200, wantRes: 100},
+			{name: "step ok", rule: "min=10,step=20", value: 210, wantRes: 210},
+			{name: "step fail", rule: "min=10,step=20", value: 200, wantErr: true},
+
+			{name: "invalid rule: negative min", rule: "min=-1", value: 1, wantErr: true},
+			{name: "invalid rule: out of range", rule: "max=256", value: 1, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := numberValidator[uint8](tt.rule)
+				var gotRes uint8
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("numberValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("numberValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("int64", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   int64
+			wantRes int64
+			wantErr bool
+		}{
+			{name: "step over the full range ok", rule: "step=3,min=-9223372036854775807", value: 9223372036854775805, wantRes: 9223372036854775805},
+			{name: "step over the full range fail", rule: "step=3,min=-9223372036854775807", value: 9223372036854775806, wantErr: true},
+			{name: "step with negative values ok", rule: "step=3,min=-9223372036854775807", value: -9223372036854775804, wantRes: -9223372036854775804},
+			{name: "step with negative values fail", rule: "step=3,min=-9223372036854775807", value: -9223372036854775805, wantErr: true},
+			{name: "large step ok", rule: "step=9223372036854775807,min=-9223372036854775807", value: 0, wantRes: 0},
+			{name: "large step fail", rule: "step=9223372036854775807,min=-9223372036854775807", value: 1, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := numberValidator[int64](tt.rule)
+				var gotRes int64
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("numberValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("numberValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   uint64
+			wantRes uint64
+			wantErr bool
+		}{
+			{name: "step at the upper bound ok", rule: "step=5", value: 18446744073709551615, wantRes: 18446744073709551615},
+			{name: "step with min at the upper bound ok", rule: "step=3,min=1", value: 18446744073709551613, wantRes: 18446744073709551613},
+			{name: "step with min at the upper bound fail", rule: "step=3,min=1", value: 18446744073709551615, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := numberValidator[uint64](tt.rule)
+				var gotRes uint64
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("numberValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("numberValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   float64
+			wantRes float64
+			wantErr bool
+		}{
+			{name: "no rules", rule: "", value: 1.5, wantRes: 1.5},
+			{name: "min ok", rule: "min=0.5", value: 0.5, wantRes: 0.5},
+			{name: "min fail", rule: "min=0.5", value: 0.49, wantErr: true},
+			{name: "max fail", rule: "max=1e3", value: 1000.1, wantErr: true},
+			{name: "clamp", rule: "min=0,max=1,clamp", value: 1.2, wantRes: 1},
+			{name: "step ok", rule: "step=0.01", value: 12.34, wantRes: 12.34},
+			{name: "step fail", rule: "step=0.01", value: 12.345, wantErr: true},
+			{name: "positive fail", rule: "positive", value: -0.1, wantErr: true},
+			{name: "NaN allowed without rules", rule: "", value: math.NaN(), wantRes: math.NaN()},
+			{name: "NaN fails with finite", rule: "finite", value: math.NaN(), wantErr: true},
+			{name: "NaN fails with min", rule: "min=0", value: math.NaN(), wantErr: true},
+			{name: "NaN fails with clamp", rule: "min=0,max=1,clamp", value: math.NaN(), wantErr: true},
+			{name: "Inf allowed without finite", rule: "", value: math.Inf(1), wantRes: math.Inf(1)},
+			{name: "Inf fails with finite", rule: "finite", value: math.Inf(-1), wantErr: true},
+			{name: "Inf clamped", rule: "max=10,clamp", value: math.Inf(1), wantRes: 10},
+			{name: "NaN fails with nonzero", rule: "nonzero", value: math.NaN(), wantErr: true},
+			{name: "NaN fails with step", rule: "step=0.5", value: math.NaN(), wantErr: true},
+			{name: "Inf fails with step", rule: "step=0.5", value: math.Inf(1), wantErr: true},
+			{name: "negative Inf fails with step", rule: "step=0.5", value: math.Inf(-1), wantErr: true},
+			{name: "Inf clamped with step", rule: "max=10,clamp,step=0.5", value: math.Inf(1), wantErr: true},
+
+			{name: "invalid rule: min not a number", rule: "min=a", value: 1, wantErr: true},
+			{name: "invalid rule: negative step", rule: "step=-0.5", value: 1, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := numberValidator[float64](tt.rule)
+				var gotRes float64
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("numberValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				// NaN is never equal to itself
+				if math.IsNaN(tt.wantRes) && math.IsNaN(gotRes) {
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("numberValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+}
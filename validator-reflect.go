@@ -0,0 +1,311 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Basic types for the kinds that can be validated, used to convert named types
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// typeValidator returns a validator for a value of type T
+// Types registered with RegisterType use the custom validator
+// Strings, numbers, `[]string` and `map[string]string` use the typed validators directly; all other types are validated using reflection
+func typeValidator[T any](rule string) (validator[T], error) {
+	var zero T
+
+	// Check if there's a custom validator for the type
+	if ct, ok := getCustomType(reflect.TypeOf(&zero).Elem()); ok {
+		return ct.typed.(func(rule string) (validator[T], error))(rule)
+	}
+
+	var (
+		f   any
+		err error
+	)
+	switch any(zero).(type) {
+	case string:
+		f, err = stringValidator(rule)
+	case []string:
+		f, err = sliceValidator[string](rule)
+	case map[string]string:
+		f, err = mapValidator[string](rule)
+	case int:
+		f, err = numberValidator[int](rule)
+	case int8:
+		f, err = numberValidator[int8](rule)
+	case int16:
+		f, err = numberValidator[int16](rule)
+	case int32:
+		f, err = numberValidator[int32](rule)
+	case int64:
+		f, err = numberValidator[int64](rule)
+	case uint:
+		f, err = numberValidator[uint](rule)
+	case uint8:
+		f, err = numberValidator[uint8](rule)
+	case uint16:
+		f, err = numberValidator[uint16](rule)
+	case uint32:
+		f, err = numberValidator[uint32](rule)
+	case uint64:
+		f, err = numberValidator[uint64](rule)
+	case float32:
+		f, err = numberValidator[float32](rule)
+	case float64:
+		f, err = numberValidator[float64](rule)
+	default:
+		rv, err := reflectValidator(reflect.TypeOf(&zero).Elem(), rule)
+		if err != nil {
+			return nil, err
+		}
+		return func(val T) (T, error) {
+			res, err := rv(reflect.ValueOf(&val).Elem())
+			if err != nil {
+				return zero, err
+			}
+			return res.Interface().(T), nil
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f.(validator[T]), nil
+}
+
+// reflectValidator returns a validator for values of type t, operating on reflect.Value objects
+// Collections (slices and maps with string keys) are supported at any level of nesting, by composing the validators for each level
+func reflectValidator(t reflect.Type, rule string) (validator[reflect.Value], error) {
+	// Check if there's a custom validator for the type
+	if ct, ok := getCustomType(t); ok {
+		return ct.reflect(rule)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return reflectWrap(t, rule, stringValidator)
+	case reflect.Int:
+		return reflectWrap(t, rule, numberValidator[int])
+	case reflect.Int8:
+		return reflectWrap(t, rule, numberValidator[int8])
+	case reflect.Int16:
+		return reflectWrap(t, rule, numberValidator[int16])
+	case reflect.Int32:
+		return reflectWrap(t, rule, numberValidator[int32])
+	case reflect.Int64:
+		return reflectWrap(t, rule, numberValidator[int64])
+	case reflect.Uint:
+		return reflectWrap(t, rule, numberValidator[uint])
+	case reflect.Uint8:
+		return reflectWrap(t, rule, numberValidator[uint8])
+	case reflect.Uint16:
+		return reflectWrap(t, rule, numberValidator[uint16])
+	case reflect.Uint32:
+		return reflectWrap(t, rule, numberValidator[uint32])
+	case reflect.Uint64:
+		return reflectWrap(t, rule, numberValidator[uint64])
+	case reflect.Float32:
+		return reflectWrap(t, rule, numberValidator[float32])
+	case reflect.Float64:
+		return reflectWrap(t, rule, numberValidator[float64])
+	case reflect.Slice:
+		return reflectSliceValidator(t, rule)
+	case reflect.Array:
+		return reflectArrayValidator(t, rule)
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String {
+			return reflectMapValidator(t, rule)
+		}
+	}
+
+	return nil, fmt.Errorf("%w %s", ErrUnsupportedType, t)
+}
+
+// reflectWrap creates a validator for a basic type T with the factory, and wraps it so it can be used with reflect.Value objects of type t
+// The type t must have T as underlying type
+func reflectWrap[T any](t reflect.Type, rule string, factory func(rule string) (validator[T], error)) (validator[reflect.Value], error) {
+	f, err := factory(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	bt := reflect.TypeOf((*T)(nil)).Elem()
+	return func(v reflect.Value) (reflect.Value, error) {
+		res, err := f(v.Convert(bt).Interface().(T))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(res).Convert(t), nil
+	}, nil
+}
+
+// reflectSliceValidator returns a validator for slices of type t, using reflection
+func reflectSliceValidator(t reflect.Type, rule string) (validator[reflect.Value], error) {
+	elem := t.Elem()
+	valueValidatorFactory := func(rule string) (validator[reflect.Value], error) {
+		return reflectValidator(elem, rule)
+	}
+
+	// Values can be sorted only if they're strings
+	var valueString func(reflect.Value) string
+	if elem.Kind() == reflect.String {
+		valueString = reflect.Value.String
+	}
+
+	f, err := sliceValidatorWith(rule, valueValidatorFactory, valueString)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) (reflect.Value, error) {
+		// Keep nil slices as nil
+		var list []reflect.Value
+		if !v.IsNil() {
+			list = make([]reflect.Value, v.Len())
+			for i := range list {
+				list[i] = v.Index(i)
+			}
+		}
+
+		list, err := f(list)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.IsNil() {
+			return v, nil
+		}
+
+		res := reflect.MakeSlice(t, len(list), len(list))
+		for i := range list {
+			res.Index(i).Set(list[i])
+		}
+		return res, nil
+	}, nil
+}
+
+// reflectArrayValidator returns a validator for arrays of type t, using reflection
+// Arrays are validated like slices, except that the "unique" rule is not allowed, as it could change the number of elements
+func reflectArrayValidator(t reflect.Type, rule string) (validator[reflect.Value], error) {
+	params, err := parseParams(rule)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := params["unique"]; ok {
+		return nil, invalidParamError("unique", "parameter 'unique' can't be used with arrays")
+	}
+
+	st := reflect.SliceOf(t.Elem())
+	f, err := reflectSliceValidator(st, rule)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) (reflect.Value, error) {
+		list := reflect.MakeSlice(st, v.Len(), v.Len())
+		reflect.Copy(list, v)
+
+		list, err := f(list)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		res := reflect.New(t).Elem()
+		reflect.Copy(res, list)
+		return res, nil
+	}, nil
+}
+
+// reflectMapValidator returns a validator for maps of type t, which must have keys of kind string, using reflection
+func reflectMapValidator(t reflect.Type, rule string) (validator[reflect.Value], error) {
+	elem := t.Elem()
+	valueValidatorFactory := func(rule string) (validator[reflect.Value], error) {
+		return reflectValidator(elem, rule)
+	}
+
+	// Values can be joined only if they're strings
+	var valueJoiner func([]reflect.Value, string) reflect.Value
+	if elem.Kind() == reflect.String {
+		valueJoiner = func(values []reflect.Value, sep string) reflect.Value {
+			strs := make([]string, len(values))
+			for i, v := range values {
+				strs[i] = v.String()
+			}
+			return reflect.ValueOf(strings.Join(strs, sep)).Convert(elem)
+		}
+	}
+
+	f, err := mapValidatorWith(rule, valueValidatorFactory, valueJoiner)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) (reflect.Value, error) {
+		// Keep nil maps as nil
+		var val map[string]reflect.Value
+		if !v.IsNil() {
+			val = make(map[string]reflect.Value, v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				val[iter.Key().String()] = iter.Value()
+			}
+		}
+
+		val, err := f(val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.IsNil() {
+			return v, nil
+		}
+
+		res := reflect.MakeMapWithSize(t, len(val))
+		for k, e := range val {
+			res.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), e)
+		}
+		return res, nil
+	}, nil
+}
+
+// validateValue validates a value whose type is not known at compile time, using reflection
+func validateValue(v reflect.Value, rule string) (reflect.Value, error) {
+	skip, err := skipValidation(v)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if skip {
+		return v, nil
+	}
+
+	return cachedReflectValidator(v.Type(), strings.TrimSpace(rule))(v)
+}
+
+// skipValidation returns true if the value doesn't need to be validated
+// Like in Validate, empty strings, slices, and maps are returned as-is, unless there's a custom validator registered for their type
+// It returns an error if there's no validator for the type of the value
+func skipValidation(v reflect.Value) (bool, error) {
+	if _, ok := getCustomType(v.Type()); ok {
+		return false, nil
+	}
+
+	kind := v.Kind()
+	switch {
+	case kind == reflect.String || kind == reflect.Slice || kind == reflect.Map || kind == reflect.Array:
+		return v.Len() == 0, nil
+	case basicTypes[kind] == nil:
+		return false, fmt.Errorf("%w %s", ErrUnsupportedType, v.Type())
+	}
+	return false, nil
+}
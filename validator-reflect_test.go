@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_typeValidator(t *testing.T) {
+	t.Run("[][]string", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   [][]string
+			wantRes [][]string
+			wantErr bool
+		}{
+			{name: "empty slice", rule: "", value: [][]string{}, wantRes: [][]string{}},
+			{name: "sanitize nested values", rule: "", value: [][]string{{" a ", "b"}, nil, {}}, wantRes: [][]string{{"a", "b"}, nil, {}}},
+			{name: "nested rules ok", rule: "value=(value=(max=3),max=3,unique),min=1", value: [][]string{{"b", "a", "b"}, {"foo"}}, wantRes: [][]string{{"a", "b"}, {"foo"}}},
+			{name: "nested rules fail inner value", rule: "value=(value=(max=3),max=3,unique),min=1", value: [][]string{{"b"}, {"hello"}}, wantErr: true},
+			{name: "nested rules fail inner length", rule: "value=(value=(max=3),max=3,unique),min=1", value: [][]string{{"a", "b", "c", "d"}}, wantErr: true},
+			{name: "nested rules fail outer length", rule: "value=(value=(max=3),max=3,unique),min=2", value: [][]string{{"a"}}, wantErr: true},
+			{name: "sort outer slice not supported", rule: "sort", value: [][]string{{"a"}}, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := typeValidator[[][]string](tt.rule)
+				var gotRes [][]string
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("typeValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("typeValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("[]map[string]string", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   []map[string]string
+			wantRes []map[string]string
+			wantErr bool
+		}{
+			{name: "sanitize nested values", rule: "", value: []map[string]string{{" a ": " b "}}, wantRes: []map[string]string{{"a": "b"}}},
+			{name: "nested rules ok", rule: "value=(key=(replace-whitespaces),value=(max=5))", value: []map[string]string{{"a b": "hello"}}, wantRes: []map[string]string{{"a_b": "hello"}}},
+			{name: "nested rules fail", rule: "value=(key=(replace-whitespaces),value=(max=5))", value: []map[string]string{{"a": "b"}, {"a b": "hello world"}}, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := typeValidator[[]map[string]string](tt.rule)
+				var gotRes []map[string]string
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("typeValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("typeValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("map[string][]string", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			rule    string
+			value   map[string][]string
+			wantRes map[string][]string
+			wantErr bool
+		}{
+			{name: "sanitize nested values", rule: "", value: map[string][]string{" a ": {" b "}}, wantRes: map[string][]string{"a": {"b"}}},
+			{name: "nested rules ok", rule: "value=(sort,max=3),max=2", value: map[string][]string{"a": {"z", "y"}}, wantRes: map[string][]string{"a": {"y", "z"}}},
+			{name: "nested rules fail", rule: "value=(sort,max=3),max=2", value: map[string][]string{"a": {"z", "y", "x", "w"}}, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator, err := typeValidator[map[string][]string](tt.rule)
+				var gotRes map[string][]string
+				if err == nil {
+					gotRes, err = validator(tt.value)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("typeValidator().validator error = %v, wantErr %v (value = %v)", err, tt.wantErr, gotRes)
+					return
+				}
+				if !reflect.DeepEqual(gotRes, tt.wantRes) {
+					t.Errorf("typeValidator().validator = %v, want %v", gotRes, tt.wantRes)
+				}
+			})
+		}
+	})
+
+	t.Run("deeper nesting and named types", func(t *testing.T) {
+		type name string
+		type names []name
+		val := map[string][]names{
+			"a": {{" x ", "y"}, {"z  z"}},
+		}
+		validator, err := typeValidator[map[string][]names]("value=(value=(value=(replace-whitespaces,max=3),sort))")
+		if err != nil {
+			t.Fatalf("typeValidator() error = %v", err)
+		}
+		gotRes, err := validator(val)
+		if err != nil {
+			t.Fatalf("typeValidator().validator error = %v", err)
+		}
+		wantRes := map[string][]names{
+			"a": {{"x", "y"}, {"z_z"}},
+		}
+		if !reflect.DeepEqual(gotRes, wantRes) {
+			t.Errorf("typeValidator().validator = %v, want %v", gotRes, wantRes)
+		}
+	})
+
+	t.Run("slices of numbers", func(t *testing.T) {
+		validator, err := typeValidator[[][]int]("value=(value=(min=1,max=10,clamp))")
+		if err != nil {
+			t.Fatalf("typeValidator() error = %v", err)
+		}
+		gotRes, err := validator([][]int{{0, 5}, {20}})
+		if err != nil {
+			t.Fatalf("typeValidator().validator error = %v", err)
+		}
+		wantRes := [][]int{{1, 5}, {10}}
+		if !reflect.DeepEqual(gotRes, wantRes) {
+			t.Errorf("typeValidator().validator = %v, want %v", gotRes, wantRes)
+		}
+	})
+
+	t.Run("unsupported types", func(t *testing.T) {
+		_, err := typeValidator[[]struct{}]("")
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("typeValidator() expected ErrUnsupportedType for slice of structs, got %v", err)
+		}
+		_, err = typeValidator[map[int]string]("")
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("typeValidator() expected ErrUnsupportedType for map with int keys, got %v", err)
+		}
+	})
+}
@@ -1,23 +1,43 @@
 package validator
 
 import (
-	"errors"
-	"fmt"
-	"reflect"
 	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
 
 	"github.com/italypaleale/go-validator/sliceutils"
 )
 
 // sliceValidator returns a validator for type `[]T`
-func sliceValidator[T any](rule string) validator[[]T] {
+func sliceValidator[T any](rule string) (validator[[]T], error) {
 	var zero T
-	errFunc := errorValidateFunc[[]T]
+
+	// Values can be sorted only if they're strings
+	var valueString func(T) string
+	if _, ok := any(zero).(string); ok {
+		valueString = func(v T) string {
+			return any(v).(string)
+		}
+	}
+
+	return sliceValidatorWith(rule, typeValidator[T], valueString)
+}
+
+// sliceValidatorWith returns a validator for type `[]T`, using valueValidatorFactory to create the validator for each value
+// The function valueString returns the string for a value, and it's used by the "sort" and "unique" rules; it can be nil if values of type T are not strings
+func sliceValidatorWith[T any](rule string, valueValidatorFactory func(rule string) (validator[T], error), valueString func(T) string) (validator[[]T], error) {
 
 	// Parse rule
 	params, err := parseParams(rule)
 	if err != nil {
-		return errFunc(err)
+		return nil, err
 	}
 
 	// Parse parameters
@@ -25,88 +45,265 @@ func sliceValidator[T any](rule string) validator[[]T] {
 	if v, ok := params["min"]; ok && v != "" {
 		min, err = strconv.Atoi(v)
 		if err != nil {
-			return errFunc(fmt.Errorf("parameter 'min' is invalid: failed to cast to int: %v", err))
+			return nil, invalidParamError("min", "parameter 'min' is invalid: failed to cast to int: %v", err)
 		}
 		if min < 1 {
-			return errFunc(errors.New("parameter 'min' must be greater than 0"))
+			return nil, invalidParamError("min", "parameter 'min' must be greater than 0")
 		}
 	}
 	max := -1
 	if v, ok := params["max"]; ok && v != "" {
 		max, err = strconv.Atoi(v)
 		if err != nil {
-			return errFunc(fmt.Errorf("parameter 'max' is invalid: failed to cast to int: %v", err))
+			return nil, invalidParamError("max", "parameter 'max' is invalid: failed to cast to int: %v", err)
 		}
 		if max < 1 {
-			return errFunc(errors.New("parameter 'max' must be greater than 0"))
+			return nil, invalidParamError("max", "parameter 'max' must be greater than 0")
 		}
 	}
 	if max > 0 && min > max {
-		return errFunc(errors.New("parameter 'max' must not be smaller than parameter 'min'"))
+		return nil, invalidParamError("max", "parameter 'max' must not be smaller than parameter 'min'")
 	}
 	sortFlag := false
-	if _, ok := params["sort"]; ok {
-		// Boolean option, with no value
+	sortCollate := false
+	if v, ok := params["sort"]; ok {
 		sortFlag = true
+		switch strings.ToLower(v) {
+		case "":
+			// Boolean option, with no value
+		case "collate":
+			sortCollate = true
+		default:
+			return nil, invalidParamError("sort", "parameter 'sort' is invalid")
+		}
 	}
-	uniqueFlag := false
-	if _, ok := params["unique"]; ok {
+	var collateOpts []collate.Option
+	lang := language.Und
+	if v, ok := params["lang"]; ok {
+		if !sortCollate {
+			return nil, invalidParamError("lang", "parameter 'lang' requires 'sort=collate'")
+		}
+		lang, err = language.Parse(v)
+		if err != nil {
+			return nil, invalidParamError("lang", "parameter 'lang' is invalid: %v", err)
+		}
+	}
+	if _, ok := params["ignorecase"]; ok {
 		// Boolean option, with no value
+		if !sortCollate {
+			return nil, invalidParamError("ignorecase", "parameter 'ignorecase' requires 'sort=collate'")
+		}
+		collateOpts = append(collateOpts, collate.IgnoreCase)
+	}
+	if _, ok := params["numeric"]; ok {
+		// Boolean option, with no value
+		if !sortCollate {
+			return nil, invalidParamError("numeric", "parameter 'numeric' requires 'sort=collate'")
+		}
+		collateOpts = append(collateOpts, collate.Numeric)
+	}
+	uniqueFlag := false
+	var uniqueKey func(string) string
+	if v, ok := params["unique"]; ok {
 		uniqueFlag = true
+		if v != "" {
+			uniqueKey, err = newUniqueKey(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	keepLast := false
+	if v, ok := params["keep"]; ok {
+		if !uniqueFlag {
+			return nil, invalidParamError("keep", "parameter 'keep' requires 'unique'")
+		}
+		switch strings.ToLower(v) {
+		case "first":
+			// Default
+		case "last":
+			keepLast = true
+		default:
+			return nil, invalidParamError("keep", "parameter 'keep' is invalid")
+		}
+	}
+
+	allErrors := false
+	if _, ok := params["all-errors"]; ok {
+		// Boolean option, with no value
+		allErrors = true
 	}
 
-	// Validator function for each value, as well as sort and unique functions
+	// Sort and unique values only if needed
 	var (
-		valueValidator        validator[T]
-		valueSorter           func([]T)     = nil
-		valueDuplicateRemover func([]T) []T = nil
-		fp                    reflect.Value
+		valueSorter           func([]T)
+		valueDuplicateRemover func([]T) []T
 	)
-	switch any(zero).(type) {
-	case string:
-		f := stringValidator(params["value"])
-		fp = reflect.ValueOf(&valueValidator).Elem()
-		fp.Set(reflect.Indirect(reflect.ValueOf(f)))
-
-		if sortFlag || uniqueFlag {
-			fp = reflect.ValueOf(&valueSorter).Elem()
-			fp.Set(reflect.Indirect(reflect.ValueOf(sliceutils.SortSlice[string])))
+	if sortFlag || uniqueFlag {
+		if valueString == nil {
+			return nil, invalidParamError("sort", "parameters 'sort' and 'unique' are only supported for slices of strings")
 		}
-		if uniqueFlag {
-			fp = reflect.ValueOf(&valueDuplicateRemover).Elem()
-			fp.Set(reflect.Indirect(reflect.ValueOf(sliceutils.RemoveDuplicatesInSortedSlice[string])))
+		if sortCollate {
+			valueSorter = newCollationSorter(valueString, lang, collateOpts...)
+		} else {
+			valueSorter = func(s []T) {
+				sliceutils.SortSliceFunc(s, func(a, b T) bool {
+					return valueString(a) < valueString(b)
+				})
+			}
+		}
+	}
+	if uniqueFlag {
+		key := valueString
+		if uniqueKey != nil {
+			key = func(v T) string {
+				return uniqueKey(valueString(v))
+			}
+		}
+		valueDuplicateRemover = func(s []T) []T {
+			return sliceutils.RemoveDuplicatesFunc(s, key, keepLast)
 		}
-	default:
-		return errFunc(fmt.Errorf("type of value '%T' is not supported", zero))
+	}
+
+	// Validator function for each value
+	valueValidator, err := valueValidatorFactory(params["value"])
+	if err != nil {
+		return nil, err
 	}
 
 	return func(list []T) (res []T, err error) {
+		// When collecting all errors, validation continues after the first failure
+		var errs ValidationErrors
+
 		// Check if we have rules
 		if min > 0 && len(list) < min {
-			return nil, fmt.Errorf("value is shorter than %d", min)
+			err = newValidationError(ErrTooShort, "min", params["min"], len(list), "value is shorter than %d", min)
+			if !allErrors {
+				return nil, err
+			}
+			errs = errs.append(err)
 		}
 		if max > 0 && len(list) > max {
-			return nil, fmt.Errorf("value is longer than %d", max)
+			err = newValidationError(ErrTooLong, "max", params["max"], len(list), "value is longer than %d", max)
+			if !allErrors {
+				return nil, err
+			}
+			errs = errs.append(err)
 		}
 
 		// Validate each item
+		var v T
 		for i := 0; i < len(list); i++ {
-			list[i], err = valueValidator(list[i])
+			v, err = valueValidator(list[i])
 			if err != nil {
-				return nil, err
+				err = prependErrorPath(err, "["+strconv.Itoa(i)+"]")
+				if !allErrors {
+					return nil, err
+				}
+				errs = errs.append(err)
+				continue
 			}
+			list[i] = v
 		}
-
-		// Sort if needed
-		if valueSorter != nil {
-			valueSorter(list)
+		if len(errs) > 0 {
+			return nil, errs
 		}
 
 		// Unique values if needed
+		// This is done before sorting, so the values that are kept depend on their original order
 		if valueDuplicateRemover != nil {
 			list = valueDuplicateRemover(list)
 		}
 
+		// Sort if needed
+		if valueSorter != nil {
+			valueSorter(list)
+		}
+
 		return list, nil
+	}, nil
+}
+
+// newCollationSorter returns a function that sorts slices using the collation rules for the language lang
+// Values that are equal according to the collation rules are sorted by their bytes, so identical values are always next to each other
+func newCollationSorter[T any](valueString func(T) string, lang language.Tag, opts ...collate.Option) func([]T) {
+	// Collator objects can't be used concurrently
+	pool := &sync.Pool{
+		New: func() any {
+			return collate.New(lang, opts...)
+		},
+	}
+	return func(s []T) {
+		c := pool.Get().(*collate.Collator)
+		defer pool.Put(c)
+		sliceutils.SortSliceFunc(s, func(a, b T) bool {
+			x, y := valueString(a), valueString(b)
+			if r := c.CompareString(x, y); r != 0 {
+				return r < 0
+			}
+			return x < y
+		})
+	}
+}
+
+// newUniqueKey returns a function that returns the key used to compare values for the "unique" rule
+// The value of the rule is a list of modes separated by "|", which are applied in this order regardless of the order in the list:
+//
+// - "width": folds fullwidth and halfwidth characters to their canonical width
+// - "accents": removes accents and other diacritics
+// - "fold": applies Unicode case folding
+// - "skeleton": computes the confusable skeleton with Skeleton
+func newUniqueKey(val string) (func(string) string, error) {
+	modes, err := parseList(val, '|')
+	if err != nil {
+		return nil, invalidParamError("unique", "parameter 'unique' is invalid: %v", err)
+	}
+	var foldWidth, removeAccents, foldCase, skeleton bool
+	for _, m := range modes {
+		switch strings.ToLower(m) {
+		case "exact":
+			// No transformation
+		case "width":
+			foldWidth = true
+		case "accents":
+			removeAccents = true
+		case "fold":
+			foldCase = true
+		case "skeleton":
+			skeleton = true
+		default:
+			return nil, invalidParamError("unique", "parameter 'unique' is invalid: unknown mode '%s'", m)
+		}
+	}
+
+	// Case folding and width folding are stateless, so the same objects can be used concurrently
+	fold := cases.Fold()
+	return func(s string) string {
+		if foldWidth {
+			s = width.Fold.String(s)
+		}
+		if removeAccents {
+			s = stripAccents(s)
+		}
+		if foldCase {
+			s = fold.String(s)
+		}
+		if skeleton {
+			s = Skeleton(s)
+		}
+		return s
+	}, nil
+}
+
+// stripAccents removes accents and other diacritics from s, by decomposing it and removing combining marks
+func stripAccents(s string) string {
+	s = norm.NFD.String(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		if !unicode.Is(unicode.Mn, r) {
+			sb.WriteRune(r)
+		}
 	}
+	return norm.NFC.String(sb.String())
 }
@@ -60,8 +60,11 @@ func Test_sliceValidator(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			validator := sliceValidator[string](tt.rule)
-			gotRes, err := validator(tt.value)
+			validator, err := sliceValidator[string](tt.rule)
+			var gotRes []string
+			if err == nil {
+				gotRes, err = validator(tt.value)
+			}
 			if (err != nil) != tt.wantErr {
 				t.Errorf("sliceValidator().validator error = %v, wantErr %v (value = %s)", err, tt.wantErr, gotRes)
 				return
@@ -72,3 +75,88 @@ func Test_sliceValidator(t *testing.T) {
 		})
 	}
 }
+
+func Test_sliceValidatorCollate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		value   []string
+		wantRes []string
+		wantErr bool
+	}{
+		{name: "byte order", rule: "sort", value: []string{"apple", "Zebra", "Zoo", "Ärger"}, wantRes: []string{"Zebra", "Zoo", "apple", "Ärger"}},
+		{name: "collate", rule: "sort=collate", value: []string{"apple", "Zebra", "Zoo", "Ärger", "Apfel"}, wantRes: []string{"Apfel", "apple", "Ärger", "Zebra", "Zoo"}},
+		{name: "collate de", rule: "sort=collate,lang=de", value: []string{"Zoo", "Ärger", "Apfel", "Birne"}, wantRes: []string{"Apfel", "Ärger", "Birne", "Zoo"}},
+		{name: "collate sv", rule: "sort=collate,lang=sv", value: []string{"Zoo", "Ärger", "Apfel", "Birne"}, wantRes: []string{"Apfel", "Birne", "Zoo", "Ärger"}},
+		{name: "collate case", rule: "sort=collate", value: []string{"B", "a", "A", "b"}, wantRes: []string{"a", "A", "b", "B"}},
+		{name: "collate ignorecase", rule: "sort=collate,ignorecase", value: []string{"b", "a", "B", "A"}, wantRes: []string{"A", "a", "B", "b"}},
+		{name: "collate without numeric", rule: "sort=collate", value: []string{"item10", "item2", "item1"}, wantRes: []string{"item1", "item10", "item2"}},
+		{name: "collate numeric", rule: "sort=collate,numeric", value: []string{"item10", "item2", "item1"}, wantRes: []string{"item1", "item2", "item10"}},
+		{name: "collate with unique", rule: "sort=collate,ignorecase,unique", value: []string{"a", "A", "a", "b"}, wantRes: []string{"A", "a", "b"}},
+		{name: "collate with values", rule: "sort=collate,value=(case=lower)", value: []string{"Zoo", "Ärger", "Apfel"}, wantRes: []string{"apfel", "ärger", "zoo"}},
+		{name: "invalid sort", rule: "sort=natural", value: []string{}, wantErr: true},
+		{name: "invalid lang", rule: "sort=collate,lang=not-a-language!", value: []string{}, wantErr: true},
+		{name: "lang without collate", rule: "sort,lang=de", value: []string{}, wantErr: true},
+		{name: "numeric without collate", rule: "numeric", value: []string{}, wantErr: true},
+		{name: "ignorecase without collate", rule: "unique,ignorecase", value: []string{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := sliceValidator[string](tt.rule)
+			var gotRes []string
+			if err == nil {
+				gotRes, err = validator(tt.value)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sliceValidator().validator error = %v, wantErr %v (value = %s)", err, tt.wantErr, gotRes)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("sliceValidator().validator = %q, want %q", gotRes, tt.wantRes)
+			}
+		})
+	}
+}
+
+func Test_sliceValidatorUniqueKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		value   []string
+		wantRes []string
+		wantErr bool
+	}{
+		{name: "exact", rule: "unique", value: []string{"Go", "go", "GO", "go"}, wantRes: []string{"GO", "Go", "go"}},
+		{name: "exact mode", rule: "unique=exact", value: []string{"Go", "go", "go"}, wantRes: []string{"Go", "go"}},
+		{name: "fold", rule: "unique=fold", value: []string{"Go", "go", "GO"}, wantRes: []string{"Go"}},
+		{name: "fold keep first", rule: "unique=fold,keep=first", value: []string{"rust", "Go", "go", "GO"}, wantRes: []string{"Go", "rust"}},
+		{name: "fold keep last", rule: "unique=fold,keep=last", value: []string{"rust", "Go", "go", "GO", "Rust"}, wantRes: []string{"GO", "Rust"}},
+		{name: "fold special cases", rule: "unique=fold", value: []string{"Straße", "STRASSE", "strasse"}, wantRes: []string{"Straße"}},
+		{name: "accents", rule: "unique=accents", value: []string{"café", "cafe", "Cafe"}, wantRes: []string{"Cafe", "café"}},
+		{name: "accents and fold", rule: "unique=(accents|fold)", value: []string{"café", "cafe", "CAFÉ"}, wantRes: []string{"café"}},
+		{name: "width", rule: "unique=width", value: []string{"ＧＯ", "GO", "ｶﾀｶﾅ", "カタカナ"}, wantRes: []string{"ＧＯ", "ｶﾀｶﾅ"}},
+		{name: "skeleton", rule: "unique=skeleton", value: []string{"paypal", "раураl", "pay pal"}, wantRes: []string{"pay pal", "paypal"}},
+		{name: "skeleton keep last", rule: "unique=skeleton,keep=last", value: []string{"paypal", "раураl"}, wantRes: []string{"раураl"}},
+		{name: "with collate", rule: "unique=fold,sort=collate", value: []string{"b", "Ärger", "B", "ärger", "a"}, wantRes: []string{"a", "Ärger", "b"}},
+		{name: "with values", rule: "unique=fold,value=(max=3,truncate)", value: []string{"Gopher", "GOPHERS", "go"}, wantRes: []string{"Gop", "go"}},
+		{name: "invalid mode", rule: "unique=soundex", value: []string{}, wantErr: true},
+		{name: "invalid keep", rule: "unique,keep=middle", value: []string{}, wantErr: true},
+		{name: "keep without unique", rule: "sort,keep=last", value: []string{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := sliceValidator[string](tt.rule)
+			var gotRes []string
+			if err == nil {
+				gotRes, err = validator(tt.value)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sliceValidator().validator error = %v, wantErr %v (value = %s)", err, tt.wantErr, gotRes)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("sliceValidator().validator = %q, want %q", gotRes, tt.wantRes)
+			}
+		})
+	}
+}
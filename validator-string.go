@@ -1,47 +1,92 @@
 package validator
 
 import (
-	"errors"
-	"fmt"
+	"html"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 	"golang.org/x/text/unicode/norm"
 )
 
 // stringValidator returns a validator for type `string`
-func stringValidator(rule string) validator[string] {
+func stringValidator(rule string) (validator[string], error) {
 	// Parse rule
-	params, err := parseParams(rule)
+	rp, err := parseRule(rule)
 	if err != nil {
-		return errorValidateFunc[string](err)
+		return nil, err
 	}
+	params := rp.toMap()
 
 	// Parse parameters
 	min := -1
 	if v, ok := params["min"]; ok && v != "" {
 		min, err = strconv.Atoi(v)
 		if err != nil {
-			return errorValidateFunc[string](fmt.Errorf("parameter 'min' is invalid: failed to cast to int: %v", err))
+			return nil, invalidParamError("min", "parameter 'min' is invalid: failed to cast to int: %v", err)
 		}
 		if min < 1 {
-			return errorValidateFunc[string](errors.New("parameter 'min' must be greater than 0"))
+			return nil, invalidParamError("min", "parameter 'min' must be greater than 0")
 		}
 	}
 	max := -1
 	if v, ok := params["max"]; ok && v != "" {
 		max, err = strconv.Atoi(v)
 		if err != nil {
-			return errorValidateFunc[string](fmt.Errorf("parameter 'max' is invalid: failed to cast to int: %v", err))
+			return nil, invalidParamError("max", "parameter 'max' is invalid: failed to cast to int: %v", err)
 		}
 		if max < 1 {
-			return errorValidateFunc[string](errors.New("parameter 'max' must be greater than 0"))
+			return nil, invalidParamError("max", "parameter 'max' must be greater than 0")
 		}
 	}
 	if max > 0 && min > max {
-		return errorValidateFunc[string](errors.New("parameter 'max' must not be smaller than parameter 'min'"))
+		return nil, invalidParamError("max", "parameter 'max' must not be smaller than parameter 'min'")
+	}
+	// Unit for the length of the string for the "min" and "max" rules
+	unit := lengthUnitBytes
+	if v, ok := params["lenunit"]; ok {
+		switch strings.ToLower(v) {
+		case "bytes":
+			unit = lengthUnitBytes
+		case "runes":
+			unit = lengthUnitRunes
+		case "graphemes":
+			unit = lengthUnitGraphemes
+		default:
+			return nil, invalidParamError("lenunit", "parameter 'lenunit' is invalid")
+		}
+	}
+	maxBytes := -1
+	if v, ok := params["maxbytes"]; ok && v != "" {
+		maxBytes, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParamError("maxbytes", "parameter 'maxbytes' is invalid: failed to cast to int: %v", err)
+		}
+		if maxBytes < 1 {
+			return nil, invalidParamError("maxbytes", "parameter 'maxbytes' must be greater than 0")
+		}
+	}
+	truncate := false
+	if _, ok := params["truncate"]; ok {
+		// Boolean option, with no value
+		if max < 1 && maxBytes < 1 {
+			return nil, invalidParamError("truncate", "parameter 'truncate' requires 'max' and/or 'maxbytes'")
+		}
+		truncate = true
+	}
+	ellipsis, hasEllipsis := rp.scalar("ellipsis")
+	if hasEllipsis {
+		if !truncate {
+			return nil, invalidParamError("ellipsis", "parameter 'ellipsis' requires 'truncate'")
+		}
+		if (max > 0 && unit.count(ellipsis) >= max) || (maxBytes > 0 && len(ellipsis) >= maxBytes) {
+			return nil, invalidParamError("ellipsis", "parameter 'ellipsis' must be shorter than the maximum length")
+		}
 	}
 	preserveWhitespace := false
 	if _, ok := params["preserve-whitespace"]; ok {
@@ -63,6 +108,21 @@ func stringValidator(rule string) validator[string] {
 		// Boolean option, with no value
 		asciiOnly = true
 	}
+	asciiTranslit := false
+	asciiReject := false
+	if v, ok := params["ascii"]; ok {
+		switch strings.ToLower(v) {
+		case "strip":
+			// Same as "asciionly"
+			asciiOnly = true
+		case "translit":
+			asciiTranslit = true
+		case "reject":
+			asciiReject = true
+		default:
+			return nil, invalidParamError("ascii", "parameter 'ascii' is invalid")
+		}
+	}
 	unorm := norm.NFC
 	if unormParam, ok := params["unorm"]; ok {
 		switch strings.ToLower(unormParam) {
@@ -75,18 +135,221 @@ func stringValidator(rule string) validator[string] {
 		case "nfkd":
 			unorm = norm.NFKD
 		default:
-			return errorValidateFunc[string](errors.New("parameter 'unorm' is invalid"))
+			return nil, invalidParamError("unorm", "parameter 'unorm' is invalid")
+		}
+	}
+
+	lang := language.Und
+	if v, ok := params["lang"]; ok {
+		if _, ok := params["case"]; !ok {
+			return nil, invalidParamError("lang", "parameter 'lang' requires 'case'")
+		}
+		lang, err = language.Parse(v)
+		if err != nil {
+			return nil, invalidParamError("lang", "parameter 'lang' is invalid: %v", err)
+		}
+	}
+	var caseMapper func(string) string
+	if v, ok := params["case"]; ok {
+		caseMapper, err = newCaseMapper(strings.ToLower(v), lang)
+		if err != nil {
+			return nil, err
 		}
 	}
 
+	slugSeparator := ""
+	if v, ok := params["slug"]; ok {
+		switch v {
+		case "":
+			slugSeparator = "-"
+		case "-", "_", ".", "~":
+			slugSeparator = v
+		default:
+			return nil, invalidParamError("slug", "parameter 'slug' is invalid: separator must be one of '-', '_', '.', '~'")
+		}
+		// Slugs are truncated at a separator, and an ellipsis would make the result not a slug
+		if hasEllipsis {
+			return nil, invalidParamError("ellipsis", "parameter 'ellipsis' can't be used with 'slug'")
+		}
+	}
+
+	maxRestriction := restrictionLevel(-1)
+	if v, ok := params["noconfusables"]; ok {
+		switch strings.ToLower(v) {
+		case "ascii":
+			maxRestriction = restrictionASCII
+		case "single":
+			maxRestriction = restrictionSingleScript
+		case "high":
+			maxRestriction = restrictionHighlyRestrictive
+		case "", "moderate":
+			maxRestriction = restrictionModeratelyRestrictive
+		default:
+			return nil, invalidParamError("noconfusables", "parameter 'noconfusables' is invalid: level must be one of 'ascii', 'single', 'high', 'moderate'")
+		}
+	}
+
+	bidiStrict := false
+	if v, ok := params["bidi"]; ok {
+		if strings.ToLower(v) != "strict" {
+			return nil, invalidParamError("bidi", "parameter 'bidi' is invalid: the only supported value is 'strict'")
+		}
+		bidiStrict = true
+	}
+	noBidiControls := false
+	if _, ok := params["nobidi-controls"]; ok {
+		// Boolean option, with no value
+		noBidiControls = true
+	}
+
+	emojiMode := ""
+	if v, ok := params["emoji"]; ok {
+		emojiMode = strings.ToLower(v)
+		switch emojiMode {
+		case "strip", "reject", "rgi-only":
+			// All good
+		default:
+			return nil, invalidParamError("emoji", "parameter 'emoji' is invalid")
+		}
+	}
+	maxEmoji := -1
+	if v, ok := params["maxemoji"]; ok {
+		maxEmoji, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParamError("maxemoji", "parameter 'maxemoji' is invalid: failed to cast to int: %v", err)
+		}
+		if maxEmoji < 0 {
+			return nil, invalidParamError("maxemoji", "parameter 'maxemoji' must not be negative")
+		}
+	}
+
+	htmlMode := ""
+	if v, ok := params["html"]; ok {
+		htmlMode = strings.ToLower(v)
+		switch htmlMode {
+		case "strip", "escape", "decode-entities":
+			// All good
+		default:
+			return nil, invalidParamError("html", "parameter 'html' is invalid")
+		}
+	}
+
+	var allowedChars, allowedScripts func(r rune) bool
+	if v, ok := params["chars"]; ok {
+		allowedChars, err = parseCharsList(v)
+		if err != nil {
+			return nil, invalidParamError("chars", "parameter 'chars' is invalid: %v", err)
+		}
+	}
+	if v, ok := params["scripts"]; ok {
+		allowedScripts, err = parseScriptsList(v)
+		if err != nil {
+			return nil, invalidParamError("scripts", "parameter 'scripts' is invalid: %v", err)
+		}
+	}
+	stripDisallowed := false
+	if v, ok := params["onbad"]; ok {
+		if allowedChars == nil && allowedScripts == nil {
+			return nil, invalidParamError("onbad", "parameter 'onbad' requires 'chars' or 'scripts'")
+		}
+		switch strings.ToLower(v) {
+		case "strip":
+			stripDisallowed = true
+		case "reject":
+			// Default
+		default:
+			return nil, invalidParamError("onbad", "parameter 'onbad' is invalid")
+		}
+	}
+	var allowRune func(r rune) bool
+	if stripDisallowed {
+		allowRune = func(r rune) bool {
+			return (allowedChars == nil || allowedChars(r)) && (allowedScripts == nil || allowedScripts(r))
+		}
+	}
+
+	var match, notMatch *regexp.Regexp
+	if v, ok := rp.scalar("match"); ok {
+		match, err = compileRegexpParam("match", v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := rp.scalar("notmatch"); ok {
+		notMatch, err = compileRegexpParam("notmatch", v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var oneOf, in *stringSet
+	if v, ok := params["oneof"]; ok {
+		list, err := parseList(v, '|')
+		if err != nil {
+			return nil, invalidParamError("oneof", "parameter 'oneof' is invalid: %v", err)
+		}
+		oneOf = newStringSet(list)
+	}
+	if v, ok := params["in"]; ok {
+		in, ok = getSet(v)
+		if !ok {
+			return nil, invalidParamError("in", "parameter 'in' is invalid: set '%s' is not registered", v)
+		}
+	}
+	ignoreCase := false
+	if _, ok := params["ignorecase"]; ok {
+		// Boolean option, with no value
+		if oneOf == nil && in == nil {
+			return nil, invalidParamError("ignorecase", "parameter 'ignorecase' requires 'oneof' or 'in'")
+		}
+		ignoreCase = true
+	}
+
+	customRules, err := customStringRuleFuncs(rp)
+	if err != nil {
+		return nil, err
+	}
+
 	return func(val string) (res string, err error) {
+		// Decode HTML entities first, so the decoded text is normalized
+		if htmlMode == "decode-entities" {
+			val = html.UnescapeString(val)
+		}
+
 		// Unicode normalization
 		val = unorm.String(val)
 
+		// Strip or escape HTML after normalizing, as normalization can turn characters such as "＜" into "<"
+		switch htmlMode {
+		case "strip":
+			// Normalize the text again, as it contains decoded entities and removing tags can join characters that compose
+			// Then, escape "<" and ">" left in the text, so the result never contains markup
+			val = htmlAngleBracketsEscaper.Replace(unorm.String(stripHTML(val)))
+		case "escape":
+			val = html.EscapeString(val)
+		}
+
 		// Trim whitespaces from each end (Unicode-aware)
 		// Note that this also trims newlines from both ends, regardless of preserveNewLines
 		val = strings.TrimSpace(val)
 
+		// Check for bidirectional control characters before they're removed when cleaning the string
+		if noBidiControls {
+			if r, pos := firstBidiControl(val); pos > 0 {
+				return "", newValidationError(ErrBidiControl, "nobidi-controls", "", len(val), "value contains a bidirectional control character %U at position %d", r, pos)
+			}
+		}
+
+		// Remove emoji before cleaning the string, so whitespaces around them are collapsed
+		if emojiMode == "strip" {
+			val = stripEmoji(val)
+		}
+
+		// Transliterate to ASCII before cleaning the string, so whitespaces are collapsed in the result
+		if asciiTranslit {
+			val = transliterateASCII(val)
+		}
+
 		// Clean the string
 		val = cleanStringInternal(val, cleanStringOpts{
 			preserveNewlines:   preserveNewlines,
@@ -98,16 +361,294 @@ func stringValidator(rule string) validator[string] {
 		// Trim whitespaces from each end again
 		val = strings.TrimSpace(val)
 
+		// Change the case, then normalize again as case mappings may not preserve the normal form
+		if caseMapper != nil {
+			val = unorm.String(caseMapper(val))
+		}
+
+		if slugSeparator != "" {
+			val = slugify(val, slugSeparator)
+		}
+
+		if asciiReject {
+			if r, pos := firstNonASCII(val); pos > 0 {
+				return "", newValidationError(ErrNotASCII, "ascii", params["ascii"], len(val), "value contains a non-ASCII character %U '%c' at position %d", r, r, pos)
+			}
+		}
+
+		// Check for characters that are not allowed, or remove them
+		// This happens after changing the case and creating the slug, so both modes of "onbad" check the same string
+		if stripDisallowed {
+			// Cleaning the string again collapses the whitespaces around the characters that are removed
+			val = strings.TrimSpace(cleanStringInternal(val, cleanStringOpts{
+				preserveNewlines:   preserveNewlines,
+				replaceWhitespaces: replaceWhitespaces,
+				preserveWhitespace: preserveWhitespace,
+				asciiOnly:          asciiOnly,
+				allowRune:          allowRune,
+			}))
+			if slugSeparator != "" {
+				val = slugify(val, slugSeparator)
+			}
+		} else {
+			if allowedChars != nil {
+				if r, pos := firstDisallowedRune(val, allowedChars); pos > 0 {
+					return "", newValidationError(ErrCharNotAllowed, "chars", params["chars"], len(val), "value contains a character that is not allowed %U '%c' at position %d", r, r, pos)
+				}
+			}
+			if allowedScripts != nil {
+				if r, pos := firstDisallowedRune(val, allowedScripts); pos > 0 {
+					return "", newValidationError(ErrCharNotAllowed, "scripts", params["scripts"], len(val), "value contains a character from a script that is not allowed %U '%c' at position %d", r, r, pos)
+				}
+			}
+		}
+
+		// Check emoji
+		if emojiMode == "reject" || emojiMode == "rgi-only" || maxEmoji >= 0 {
+			count := 0
+			findEmoji(val, func(cluster string, kind emojiKind, pos int) bool {
+				switch {
+				case emojiMode == "reject":
+					err = newValidationError(ErrEmoji, "emoji", params["emoji"], len(val), "value contains an emoji at position %d", pos)
+				case emojiMode == "rgi-only" && kind == emojiInvalid:
+					err = newValidationError(ErrInvalidEmoji, "emoji", params["emoji"], len(val), "value contains an invalid emoji sequence %+q at position %d", cluster, pos)
+				case maxEmoji >= 0 && count >= maxEmoji:
+					err = newValidationError(ErrTooManyEmoji, "maxemoji", params["maxemoji"], len(val), "value contains more than %d emoji", maxEmoji)
+				}
+				count++
+				return err == nil
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+
+		// Check that right-to-left text satisfies the Bidi Rule
+		if bidiStrict {
+			if r, pos, reason := checkBidiRule(val); pos > 0 {
+				return "", newValidationError(ErrBidiRule, "bidi", params["bidi"], len(val), "value does not satisfy the bidi rule: character %U '%c' at position %d %s", r, r, pos, reason)
+			}
+		}
+
+		// Check for strings that mix scripts or numbering systems, which can be used for spoofing
+		if maxRestriction >= 0 {
+			level, scripts := getRestrictionLevel(val)
+			if level > maxRestriction {
+				return "", newValidationError(ErrConfusable, "noconfusables", params["noconfusables"], len(val), "value mixes characters from the scripts %s", strings.Join(scripts, ", "))
+			}
+			if hasMixedNumbers(val) {
+				return "", newValidationError(ErrConfusable, "noconfusables", params["noconfusables"], len(val), "value mixes digits from different numbering systems")
+			}
+		}
+
+		// Execute custom rules
+		for _, fn := range customRules {
+			val, err = fn(val)
+			if err != nil {
+				return "", err
+			}
+		}
+
 		// Check if we have length rules
-		if min > 0 && len(val) < min {
-			return "", fmt.Errorf("value is shorter than %d", min)
+		if truncate {
+			val = truncateString(val, unit, max, maxBytes, ellipsis, htmlMode == "escape" || htmlMode == "strip")
+		}
+		if slugSeparator != "" {
+			// Slugs are always truncated, without leaving a separator at the end
+			// Slugs contain ASCII characters only, so all length units are the same
+			if max > 0 && len(val) > max {
+				val = strings.TrimRight(val[:max], slugSeparator)
+			}
+			if maxBytes > 0 && len(val) > maxBytes {
+				val = strings.TrimRight(val[:maxBytes], slugSeparator)
+			}
+		}
+		if min > 0 || max > 0 {
+			l := unit.count(val)
+			if min > 0 && l < min {
+				return "", newValidationError(ErrTooShort, "min", params["min"], l, "value is shorter than %d", min)
+			}
+			if max > 0 && l > max {
+				return "", newValidationError(ErrTooLong, "max", params["max"], l, "value is longer than %d", max)
+			}
 		}
-		if max > 0 && len(val) > max {
-			return "", fmt.Errorf("value is longer than %d", max)
+		if maxBytes > 0 && len(val) > maxBytes {
+			return "", newValidationError(ErrTooLong, "maxbytes", params["maxbytes"], len(val), "value is longer than %d bytes", maxBytes)
+		}
+
+		// Check if we have rules for allowed values
+		// When ignoring case, the value is replaced with the one in the list
+		if oneOf != nil {
+			v, ok := oneOf.lookup(val, ignoreCase)
+			if !ok {
+				return "", newValidationError(ErrNotAllowed, "oneof", params["oneof"], len(val), "value is not one of the allowed values")
+			}
+			val = v
+		}
+		if in != nil {
+			v, ok := in.lookup(val, ignoreCase)
+			if !ok {
+				return "", newValidationError(ErrNotAllowed, "in", params["in"], len(val), "value is not in the set '%s'", params["in"])
+			}
+			val = v
+		}
+
+		// Check if we have pattern rules
+		if match != nil && !match.MatchString(val) {
+			return "", newValidationError(ErrPatternMismatch, "match", match.String(), len(val), "value does not match the pattern")
+		}
+		if notMatch != nil && notMatch.MatchString(val) {
+			return "", newValidationError(ErrPatternForbidden, "notmatch", notMatch.String(), len(val), "value matches a forbidden pattern")
 		}
 
 		return val, nil
+	}, nil
+}
+
+// slugify converts s to a slug that contains only lowercase ASCII letters and digits, with runs of other characters replaced by sep
+// Leading and trailing separators are removed
+func slugify(s string, sep string) string {
+	s = transliterateASCII(s)
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	pendingSep := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			c += 'a' - 'A'
+		case (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'):
+			// Nop
+		default:
+			pendingSep = true
+			continue
+		}
+
+		// Add a single separator for each run of other characters, but not at the beginning
+		if pendingSep && sb.Len() > 0 {
+			sb.WriteString(sep)
+		}
+		pendingSep = false
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// lengthUnit is the unit used to measure the length of strings
+type lengthUnit int
+
+const (
+	lengthUnitBytes lengthUnit = iota
+	lengthUnitRunes
+	lengthUnitGraphemes
+)
+
+// count returns the length of s in the unit
+func (u lengthUnit) count(s string) int {
+	switch u {
+	case lengthUnitRunes:
+		return utf8.RuneCountInString(s)
+	case lengthUnitGraphemes:
+		return graphemeCount(s)
+	default:
+		return len(s)
+	}
+}
+
+// truncateString truncates val so it's at most max long (in the given unit) and at most maxBytes bytes long, including the ellipsis, which is appended if the string is truncated
+// Limits that are not greater than 0 are ignored
+// The string is always cut at the boundary of a grapheme cluster, so multi-byte characters, combining marks, and emoji sequences are never split
+// If keepEntities is true, HTML entities (such as "&amp;") are not split
+func truncateString(val string, unit lengthUnit, max int, maxBytes int, ellipsis string, keepEntities bool) string {
+	if (max < 1 || unit.count(val) <= max) && (maxBytes < 1 || len(val) <= maxBytes) {
+		return val
+	}
+
+	// Reserve space for the ellipsis
+	if max > 0 {
+		max -= unit.count(ellipsis)
+	}
+	if maxBytes > 0 {
+		maxBytes -= len(ellipsis)
+	}
+
+	var pos, l int
+	for pos < len(val) {
+		n := nextGrapheme(val[pos:])
+		if keepEntities && val[pos] == '&' {
+			if end := strings.IndexByte(val[pos:], ';'); end > 1 && end <= maxHTMLEntityLength && isHTMLEntityName(val[pos+1:pos+end]) {
+				n = end + 1
+			}
+		}
+		l += unit.count(val[pos : pos+n])
+		if (max > 0 && l > max) || (maxBytes > 0 && pos+n > maxBytes) {
+			break
+		}
+		pos += n
+	}
+
+	// Remove whitespace left at the end of the truncated string
+	return strings.TrimRightFunc(val[:pos], unicode.IsSpace) + ellipsis
+}
+
+// newCaseMapper returns a function that changes the case of strings for the "case" rule, using the rules for the language lang
+// Possible values for mode are "lower", "upper", "title", and "fold"
+func newCaseMapper(mode string, lang language.Tag) (func(string) string, error) {
+	var newCaser func() cases.Caser
+	switch mode {
+	case "lower":
+		newCaser = func() cases.Caser { return cases.Lower(lang) }
+	case "upper":
+		newCaser = func() cases.Caser { return cases.Upper(lang) }
+	case "title":
+		newCaser = func() cases.Caser { return cases.Title(lang) }
+	case "fold":
+		fold := cases.Fold()
+		if lang == language.Und {
+			// Case folding is stateless, so the same Caser can be used concurrently
+			return fold.String, nil
+		}
+		// Case folding is not language-aware, so strings are converted to lower case with the rules for the language first
+		// This is needed for example for the Turkish dotted and dotless i
+		newCaser = func() cases.Caser { return cases.Lower(lang) }
+		lower := newCaserPool(newCaser)
+		return func(s string) string {
+			return fold.String(lower(s))
+		}, nil
+	default:
+		return nil, invalidParamError("case", "parameter 'case' is invalid")
 	}
+
+	return newCaserPool(newCaser), nil
+}
+
+// newCaserPool returns a function that maps strings using Caser objects from a pool
+// This is needed because Caser objects can't be used concurrently
+func newCaserPool(newCaser func() cases.Caser) func(string) string {
+	pool := &sync.Pool{
+		New: func() any {
+			c := newCaser()
+			return &c
+		},
+	}
+	return func(s string) string {
+		c := pool.Get().(*cases.Caser)
+		defer pool.Put(c)
+		return c.String(s)
+	}
+}
+
+// compileRegexpParam compiles the regular expression in the value of the parameter with the given name
+func compileRegexpParam(name string, v string) (*regexp.Regexp, error) {
+	if v == "" {
+		return nil, invalidParamError(name, "parameter '%s' must not be empty", name)
+	}
+	re, err := regexp.Compile(v)
+	if err != nil {
+		return nil, invalidParamError(name, "parameter '%s' is not a valid regular expression: %v", name, err)
+	}
+	return re, nil
 }
 
 type cleanStringOpts struct {
@@ -115,6 +656,9 @@ type cleanStringOpts struct {
 	replaceWhitespaces bool
 	preserveWhitespace bool
 	asciiOnly          bool
+	// If set, characters for which the function returns false are removed
+	// Whitespace characters are checked after they're replaced
+	allowRune func(r rune) bool
 }
 
 // Iterate through the string to strip control characters
@@ -125,6 +669,8 @@ func cleanStringInternal(val string, opts cleanStringOpts) string {
 		n         int
 		a         int
 		lastSpace bool
+		// Position where the tags of a subdivision flag end, if the string contains one
+		tagsEnd int
 	)
 	out := make([]byte, len(val))
 	for i, w := 0, 0; i < len(val); i += w {
@@ -146,19 +692,26 @@ func cleanStringInternal(val string, opts cleanStringOpts) string {
 		// - tabs (0x09) (which are replaced to regular spaces if preserve-whitespace is not present)
 		// - newlines (0x0A)
 		// - Zero-Width Joiner (ZWJ), which is used by emojis (U+200D)
-		if r != 0x09 && r != 0x0A && r != 0x200D && unicode.Is(unicode.C, r) {
+		// - Tags (U+E0020-U+E007F) in subdivision flags that are recommended for general interchange, such as the flag of England
+		if r != 0x09 && r != 0x0A && r != 0x200D && !(i < tagsEnd && isEmojiTag(r)) && unicode.Is(unicode.C, r) {
 			continue
 		}
 
 		// Add runes that are not spaces right away
 		if !unicode.IsSpace(r) {
+			if opts.allowRune != nil && !opts.allowRune(r) {
+				continue
+			}
 			lastSpace = false
 			a = utf8.EncodeRune(out[n:], r)
 			n += a
+			if r == emojiBlackFlag {
+				tagsEnd = i + w + rgiSubdivisionTagsLength(val[i+w:])
+			}
 			continue
 		}
 		// If preserving newlines, keep those too
-		if opts.preserveNewlines && r == '\n' {
+		if opts.preserveNewlines && r == '\n' && (opts.allowRune == nil || opts.allowRune('\n')) {
 			lastSpace = true
 			out[n] = '\n'
 			n++
@@ -169,20 +722,20 @@ func cleanStringInternal(val string, opts cleanStringOpts) string {
 			continue
 		}
 
-		lastSpace = true
 		if opts.replaceWhitespaces {
 			// Replace with an underscore
-			out[n] = '_'
-			n++
+			r = '_'
 		} else if !opts.preserveWhitespace {
 			// Replace with a regular space
-			out[n] = ' '
-			n++
-		} else {
-			// Add the rune as-is
-			a = utf8.EncodeRune(out[n:], r)
-			n += a
+			r = ' '
+		}
+		if opts.allowRune != nil && !opts.allowRune(r) {
+			continue
 		}
+
+		lastSpace = true
+		a = utf8.EncodeRune(out[n:], r)
+		n += a
 	}
 
 	return string(out[:n])